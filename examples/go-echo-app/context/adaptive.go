@@ -0,0 +1,89 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// adaptiveWindow is how many recent latencies are kept per method.
+	adaptiveWindow = 200
+	// adaptiveMinSamples is how many successful calls a method needs before
+	// its deadline is derived from them; until then max applies.
+	adaptiveMinSamples = 20
+)
+
+// WithAdaptiveTimeout bounds each call to multiplier times the p99 latency
+// of recent attempts of the same method, clamped to [min, max]. Attempts
+// cut off by the adaptive deadline count as taking the full deadline, so it
+// grows again when the backend slows down. A method with too little
+// history gets max. A deadline already on the call's context still wins,
+// and WithCallTimeout overrides the adaptive deadline for that call.
+func WithAdaptiveTimeout(min, max time.Duration, multiplier float64) Option {
+	return func(c *Client) {
+		c.adaptive = &adaptiveTimeout{
+			min:        min,
+			max:        max,
+			multiplier: multiplier,
+			windows:    make(map[string]*latencyWindow),
+		}
+	}
+}
+
+type adaptiveTimeout struct {
+	min, max   time.Duration
+	multiplier float64
+
+	mu      sync.Mutex
+	windows map[string]*latencyWindow
+}
+
+// latencyWindow is a ring buffer of the most recent latencies.
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+// observe records one attempt that took d. Failed attempts are ignored
+// unless they hit the adaptive deadline, which says the latency is at least
+// d.
+func (a *adaptiveTimeout) observe(op string, d time.Duration, err error, deadlineHit bool) {
+	if err == nil || deadlineHit && errors.Is(err, context.DeadlineExceeded) {
+		a.record(op, d)
+	}
+}
+
+func (a *adaptiveTimeout) record(op string, d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	w := a.windows[op]
+	if w == nil {
+		w = &latencyWindow{}
+		a.windows[op] = w
+	}
+	if len(w.samples) < adaptiveWindow {
+		w.samples = append(w.samples, d)
+		return
+	}
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % adaptiveWindow
+}
+
+func (a *adaptiveTimeout) timeout(op string) time.Duration {
+	a.mu.Lock()
+	w := a.windows[op]
+	if w == nil || len(w.samples) < adaptiveMinSamples {
+		a.mu.Unlock()
+		return a.max
+	}
+	sorted := append([]time.Duration(nil), w.samples...)
+	a.mu.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	p99 := sorted[(len(sorted)*99-1)/100]
+	d := time.Duration(float64(p99) * a.multiplier)
+	return min(max(d, a.min), a.max)
+}
@@ -0,0 +1,74 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveTimeout(t *testing.T) {
+	c := NewClient("http://unused", WithAdaptiveTimeout(50*time.Millisecond, time.Second, 2))
+	a := c.adaptive
+	if got := a.timeout("Query"); got != time.Second {
+		t.Errorf("no history: timeout = %v", got)
+	}
+	for i := 0; i < 100; i++ {
+		a.record("Query", 100*time.Millisecond)
+	}
+	if got := a.timeout("Query"); got != 200*time.Millisecond {
+		t.Errorf("timeout = %v, want 200ms", got)
+	}
+	for i := 0; i < 100; i++ {
+		a.record("Ping", time.Millisecond)
+	}
+	if got := a.timeout("Ping"); got != 50*time.Millisecond {
+		t.Errorf("clamped timeout = %v, want 50ms", got)
+	}
+
+	// A tighter context deadline still wins.
+	block := make(chan struct{})
+	defer close(block)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	c.BaseURL = srv.URL
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := c.Ping(ctx); err == nil {
+		t.Fatal("expected timeout")
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("ping took %v", d)
+	}
+}
+
+func TestAdaptiveTimeoutRecovers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(60 * time.Millisecond):
+			w.Write([]byte(`{"domains":[]}`))
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithAdaptiveTimeout(10*time.Millisecond, time.Second, 2), WithRetry(RetryPolicy{MaxAttempts: 1}))
+	for i := 0; i < adaptiveMinSamples; i++ {
+		c.adaptive.record("ListDomains", time.Millisecond)
+	}
+	// The backend is now far slower than history says. Timed-out attempts
+	// must widen the deadline until calls get through again.
+	for i := 0; i < 10; i++ {
+		if _, err := c.ListDomains(context.Background()); err == nil {
+			return
+		}
+	}
+	t.Fatalf("still timing out; deadline = %v", c.adaptive.timeout("ListDomains"))
+}
@@ -0,0 +1,289 @@
+package context
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Visibility controls who can see a decision. It narrows access within the
+// project or tenant the server associates with the caller's credentials; it
+// never widens it, so an org-wide decision is still invisible to other
+// tenants.
+type Visibility string
+
+const (
+	VisibilityPrivate Visibility = "private"
+	VisibilityTeam    Visibility = "team"
+	VisibilityOrg     Visibility = "org"
+)
+
+// WithVisibility sets the visibility recorded on ADRs that don't specify
+// one. It does not filter reads; set QueryRequest.Visibility or
+// ADRFilter.Visibility for that.
+func WithVisibility(v Visibility) Option {
+	return func(c *Client) {
+		c.visibility = v
+	}
+}
+
+// ADRFilter narrows ListADRs. Zero fields are not sent.
+type ADRFilter struct {
+	Visibility Visibility
+	Tags       []string
+	Owners     []string
+	// IncludeDeleted lists soft-deleted decisions too.
+	IncludeDeleted bool
+
+	page
+}
+
+func (f ADRFilter) values() url.Values {
+	q := url.Values{}
+	if f.Visibility != "" {
+		q.Set("visibility", string(f.Visibility))
+	}
+	if len(f.Tags) > 0 {
+		q.Set("tags", strings.Join(f.Tags, ","))
+	}
+	if len(f.Owners) > 0 {
+		q.Set("owners", strings.Join(f.Owners, ","))
+	}
+	if f.IncludeDeleted {
+		q.Set("include_deleted", "true")
+	}
+	f.page.set(q)
+	return q
+}
+
+func (c *Client) GetADR(ctx context.Context, id string, opts ...CallOption) (*Decision, error) {
+	var resp struct {
+		ADR Decision `json:"adr"`
+	}
+	var header http.Header
+	err := c.do(ctx, call{
+		op:         "GetADR",
+		method:     http.MethodGet,
+		path:       "/adr/" + url.PathEscape(id),
+		out:        &resp,
+		opts:       opts,
+		respHeader: &header,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.ADR.ETag = header.Get("ETag")
+	return &resp.ADR, nil
+}
+
+func (c *Client) ListADRs(ctx context.Context, filter ADRFilter, opts ...CallOption) ([]Decision, error) {
+	filter.Tags = c.tags(filter.Tags)
+	var adrs []Decision
+	err := c.do(ctx, call{
+		op:     "ListADRs",
+		method: http.MethodGet,
+		path:   "/adr",
+		query:  filter.values(),
+		out:    &adrs,
+		opts:   opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return adrs, nil
+}
+
+// WithContinueOnError makes a bulk write create the valid records even when
+// others fail. Without it the server rejects the whole batch.
+func WithContinueOnError() CallOption {
+	return func(o *callOptions) {
+		o.continueOnError = true
+	}
+}
+
+// CreateADRs creates reqs in one request to /adr/bulk and returns their IDs
+// in input order. Records that fail Validate or that the server rejects are
+// reported by index through a *BatchError and have an empty ID. Invalid
+// records are never sent; without WithContinueOnError any invalid record
+// aborts the whole batch before the request is made.
+func (c *Client) CreateADRs(ctx context.Context, reqs []ADRRequest, opts ...CallOption) ([]string, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	continueOnError := resolveCallOptions(opts).continueOnError
+
+	ids := make([]string, len(reqs))
+	errs := map[int]error{}
+	var (
+		adrs []adrPayload
+		sent []int // index in reqs of each entry in adrs
+	)
+	for i, req := range reqs {
+		if err := req.Validate(); err != nil {
+			errs[i] = err
+			continue
+		}
+		if req.Visibility == "" {
+			req.Visibility = c.visibility
+		}
+		req.Tags = c.tags(req.Tags)
+		adrs = append(adrs, req.payload())
+		sent = append(sent, i)
+	}
+	if len(adrs) == 0 || (len(errs) > 0 && !continueOnError) {
+		return ids, batchErr(errs)
+	}
+
+	var resp struct {
+		IDs    []string       `json:"ids"`
+		Errors map[int]string `json:"errors"`
+	}
+	err := c.do(ctx, call{
+		op:     "CreateADRs",
+		method: http.MethodPost,
+		path:   "/adr/bulk",
+		in: map[string]any{
+			"adrs":              adrs,
+			"continue_on_error": continueOnError,
+		},
+		out:   &resp,
+		opts:  opts,
+		keyed: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for j, id := range resp.IDs {
+		if j < len(sent) {
+			ids[sent[j]] = id
+		}
+	}
+	for j, msg := range resp.Errors {
+		if j >= 0 && j < len(sent) {
+			errs[sent[j]] = errors.New(msg)
+		}
+	}
+	return ids, batchErr(errs)
+}
+
+// conflictMinScore is the relevance above which an untagged match is still
+// worth a reviewer's attention.
+const conflictMinScore = 0.5
+
+// FindConflictingADRs returns existing decisions that may contradict
+// proposed, most relevant first. It is a heuristic: it searches on the
+// proposal's title, decision and tags and keeps matches that share a tag or
+// score highly. Superseded decisions are no longer in force and are left
+// out. Results are candidates for human review, not confirmed conflicts.
+func (c *Client) FindConflictingADRs(ctx context.Context, proposed ADRRequest, opts ...CallOption) ([]Decision, error) {
+	query := strings.Join(append([]string{proposed.Title, proposed.Decision}, proposed.Tags...), " ")
+	resp, err := c.Query(ctx, QueryRequest{Query: query}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]struct{}, len(proposed.Tags))
+	for _, t := range proposed.Tags {
+		tags[strings.ToLower(t)] = struct{}{}
+	}
+	sharesTag := func(d Decision) bool {
+		for _, t := range d.Tags {
+			if _, ok := tags[strings.ToLower(t)]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var candidates []Decision
+	for _, d := range resp.KeyDecisions {
+		if d.SupersededBy == "" && (sharesTag(d) || d.Score >= conflictMinScore) {
+			candidates = append(candidates, d)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates, nil
+}
+
+// GetOrCreateADR returns the ADR whose title matches req's, ignoring case
+// and spacing, creating it from req if there is none. The bool reports
+// whether it was created. Servers with /adr/get-or-create do this
+// atomically. Otherwise the client searches first and then creates with an
+// idempotency key derived from the title, which collapses concurrent
+// creates of the same title into one on servers that honor the key.
+func (c *Client) GetOrCreateADR(ctx context.Context, req ADRRequest, opts ...CallOption) (*Decision, bool, error) {
+	if err := req.Validate(); err != nil {
+		return nil, false, err
+	}
+	if req.Visibility == "" {
+		req.Visibility = c.visibility
+	}
+	req.Tags = c.tags(req.Tags)
+
+	key := titleKey(req.Title)
+	var resp struct {
+		ADR     Decision `json:"adr"`
+		Created bool     `json:"created"`
+	}
+	err := c.do(ctx, call{
+		op:     "GetOrCreateADR",
+		method: http.MethodPost,
+		path:   "/adr/get-or-create",
+		in:     req.payload(),
+		out:    &resp,
+		opts:   append([]CallOption{WithIdempotencyKey(key)}, opts...),
+	})
+	if !isUnsupported(err) {
+		if err != nil {
+			return nil, false, err
+		}
+		return &resp.ADR, resp.Created, nil
+	}
+
+	found, err := c.fetchQuery(ctx, QueryRequest{Query: req.Title}, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	title := normalizeText(req.Title)
+	for _, d := range found.KeyDecisions {
+		if normalizeText(d.Title) == title {
+			return &d, false, nil
+		}
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	err = c.do(ctx, call{
+		op:     "GetOrCreateADR",
+		method: http.MethodPost,
+		path:   "/adr",
+		in:     req.payload(),
+		out:    &created,
+		opts:   append([]CallOption{WithIdempotencyKey(key)}, opts...),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	d, err := c.GetADR(ctx, created.ID, opts...)
+	if err != nil {
+		return nil, false, err
+	}
+	return d, true, nil
+}
+
+// titleKey is the idempotency key for creating an ADR titled title.
+func titleKey(title string) string {
+	sum := sha256.Sum256([]byte(normalizeText(title)))
+	return "adr-title-" + hex.EncodeToString(sum[:16])
+}
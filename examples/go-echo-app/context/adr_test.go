@@ -0,0 +1,271 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSoftDelete(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.EscapedPath()+"?"+r.URL.RawQuery)
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`[{"id":"a","title":"Use gin","deleted_at":"2026-01-02T00:00:00Z"}]`))
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+	ctx := context.Background()
+
+	if err := c.DeleteADR(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteADR(ctx, "a", WithSoftDelete()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RestoreADR(ctx, "a/b"); err != nil {
+		t.Fatal(err)
+	}
+	adrs, err := c.ListADRs(ctx, ADRFilter{IncludeDeleted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"DELETE /adr/a?",
+		"DELETE /adr/a?soft=true",
+		"POST /adr/a%2Fb/restore?",
+		"GET /adr?include_deleted=true",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+	if len(adrs) != 1 || adrs[0].DeletedAt.IsZero() {
+		t.Errorf("adrs = %+v", adrs)
+	}
+}
+
+func TestVisibility(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		got []Visibility
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodGet {
+			got = append(got, Visibility(r.URL.Query().Get("visibility")))
+			w.Write([]byte("[]"))
+			return
+		}
+		type adr struct {
+			Visibility Visibility `json:"visibility"`
+		}
+		var body struct {
+			adr
+			ADRs       []adr `json:"adrs"`
+			Operations []struct {
+				Data adr `json:"data"`
+			} `json:"operations"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("%s: %v", r.URL.Path, err)
+		}
+		switch r.URL.Path {
+		case "/adr":
+			got = append(got, body.Visibility)
+		case "/adr/bulk":
+			for _, a := range body.ADRs {
+				got = append(got, a.Visibility)
+			}
+			json.NewEncoder(w).Encode(map[string][]string{"ids": {"adr-1", "adr-2"}})
+			return
+		case "/batch/transaction":
+			for _, op := range body.Operations {
+				got = append(got, op.Data.Visibility)
+			}
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := NewClient(srv.URL, WithVisibility(VisibilityTeam))
+	adr := ADRRequest{Title: "t", Context: "c", Decision: "d"}
+	org := adr
+	org.Visibility = VisibilityOrg
+	if err := c.CreateADR(ctx, adr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateADRs(ctx, []ADRRequest{adr, org}); err != nil {
+		t.Fatal(err)
+	}
+	err := c.Transaction(ctx, func(tx *Tx) error {
+		return tx.CreateADR(adr)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListADRs(ctx, ADRFilter{Visibility: VisibilityPrivate}); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []Visibility{VisibilityTeam, VisibilityTeam, VisibilityOrg, VisibilityTeam, VisibilityPrivate}
+	if !slices.Equal(got, want) {
+		t.Errorf("visibility = %v, want %v", got, want)
+	}
+}
+
+func TestGetOrCreateADRFallback(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/adr/get-or-create":
+			http.NotFound(w, r)
+		case r.URL.Path == "/context/query":
+			json.NewEncoder(w).Encode(QueryResponse{KeyDecisions: []Decision{{ID: "ADR-1", Title: "Use  Postgres"}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/adr":
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			w.Write([]byte(`{"id":"ADR-2"}`))
+		case r.URL.Path == "/adr/ADR-2":
+			w.Write([]byte(`{"adr":{"id":"ADR-2","title":"Use Redis"}}`))
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	d, created, err := c.GetOrCreateADR(context.Background(), ADRRequest{Title: "use postgres", Context: "c", Decision: "d"})
+	if err != nil || created || d.ID != "ADR-1" {
+		t.Fatalf("existing: %+v, %v, %v", d, created, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		d, created, err = c.GetOrCreateADR(context.Background(), ADRRequest{Title: "Use Redis", Context: "c", Decision: "d"})
+		if err != nil || !created || d.ID != "ADR-2" {
+			t.Fatalf("new: %+v, %v, %v", d, created, err)
+		}
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("idempotency keys = %q", keys)
+	}
+}
+
+func TestCreateADRsContinueOnError(t *testing.T) {
+	var requests int
+	var sent struct {
+		ADRs            []ADRRequest `json:"adrs"`
+		ContinueOnError bool         `json:"continue_on_error"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Write([]byte(`{"ids":["id-a","","id-c"],"errors":{"1":"duplicate title"}}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	reqs := []ADRRequest{
+		{Title: "a", Decision: "d"},
+		{Title: "no decision"},
+		{Title: "b", Decision: "d"},
+		{Title: "c", Decision: "d"},
+	}
+
+	// Without the option an invalid record stops the batch before sending.
+	_, err := c.CreateADRs(context.Background(), reqs)
+	var be *BatchError
+	if !errors.As(err, &be) || len(be.Errors) != 1 || be.Errors[1] == nil || requests != 0 {
+		t.Fatalf("err = %v after %d requests", err, requests)
+	}
+
+	ids, err := c.CreateADRs(context.Background(), reqs, WithContinueOnError())
+	if !slices.Equal(ids, []string{"id-a", "", "", "id-c"}) {
+		t.Errorf("ids = %q", ids)
+	}
+	var verr *ValidationError
+	if !errors.As(err, &be) || len(be.Errors) != 2 || !errors.As(be.Errors[1], &verr) ||
+		be.Errors[2] == nil || be.Errors[2].Error() != "duplicate title" {
+		t.Errorf("err = %v", err)
+	}
+	if len(sent.ADRs) != 3 || !sent.ContinueOnError || sent.ADRs[1].Title != "b" {
+		t.Errorf("sent %+v", sent)
+	}
+}
+
+func TestADROwners(t *testing.T) {
+	var created ADRRequest
+	var owners string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			owners = r.URL.Query().Get("owners")
+			w.Write([]byte(`[{"id":"adr-1","owners":["@web","@platform"]}]`))
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	err := c.CreateADR(context.Background(), ADRRequest{Title: "t", Decision: "d", Owners: []string{"@web", "@platform"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(created.Owners, []string{"@web", "@platform"}) {
+		t.Errorf("created owners = %q", created.Owners)
+	}
+
+	adrs, err := c.ListADRs(context.Background(), ADRFilter{Owners: []string{"@web", "@platform"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owners != "@web,@platform" {
+		t.Errorf("owners param = %q", owners)
+	}
+	if len(adrs) != 1 || !slices.Equal(adrs[0].Owners, []string{"@web", "@platform"}) {
+		t.Errorf("adrs = %+v", adrs)
+	}
+}
+
+func TestFindConflictingADRs(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+	proposed := ADRRequest{Title: "Use Gin", Decision: "Gin router", Tags: []string{"HTTP"}}
+
+	for _, tt := range []struct {
+		name, body, want string
+	}{
+		{"conflict", `{"key_decisions":[
+			{"id":"tagged","score":0.2,"tags":["http"]},
+			{"id":"close","score":0.7},
+			{"id":"weak","score":0.3,"tags":["db"]}]}`, "close,tagged"},
+		{"none", `{"key_decisions":[{"id":"weak","score":0.3,"tags":["db"]}]}`, ""},
+		{"superseded", `{"key_decisions":[{"id":"old","score":0.9,"tags":["http"],"superseded_by":"new"}]}`, ""},
+	} {
+		body = tt.body
+		got, err := c.FindConflictingADRs(context.Background(), proposed)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var ids []string
+		for _, d := range got {
+			ids = append(ids, d.ID)
+		}
+		if s := strings.Join(ids, ","); s != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package context
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ADRDedupStrategy is how the server recognizes a new ADR as a duplicate of
+// an existing one.
+type ADRDedupStrategy string
+
+const (
+	DedupNone ADRDedupStrategy = "none"
+	// DedupTitle matches on the title, ignoring case and spacing.
+	DedupTitle ADRDedupStrategy = "title"
+	// DedupContentHash matches on ContentHash, so retitled copies of the
+	// same decision are caught too.
+	DedupContentHash ADRDedupStrategy = "content_hash"
+)
+
+func (s ADRDedupStrategy) valid() bool {
+	switch s {
+	case DedupNone, DedupTitle, DedupContentHash:
+		return true
+	}
+	return false
+}
+
+// ContentHash is a hex SHA-256 over r's Title, Context and Decision, each
+// lower-cased with runs of whitespace collapsed, so cosmetic edits don't
+// change it. The client sends it with every ADR it creates as
+// content_hash.
+func (r ADRRequest) ContentHash() string {
+	h := sha256.New()
+	for _, s := range []string{r.Title, r.Context, r.Decision} {
+		h.Write([]byte(normalizeText(s)))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// adrPayload is an ADRRequest as sent, with its content hash filled in and
+// OptionsConsideredOrdered copied into the map form.
+// It is a plain struct rather than a MarshalJSON method so that every
+// Codec encodes the hash.
+type adrPayload struct {
+	ADRRequest
+	ContentHash string `json:"content_hash"`
+}
+
+func (r ADRRequest) payload() adrPayload {
+	if r.OptionsConsidered == nil {
+		r.OptionsConsidered = optionsMap(r.OptionsConsideredOrdered)
+	}
+	return adrPayload{ADRRequest: r, ContentHash: r.ContentHash()}
+}
+
+// normalizeText lower-cases s and collapses runs of whitespace.
+func normalizeText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,33 @@
+package context
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestADRContentHash(t *testing.T) {
+	a := ADRRequest{Title: "Use Redis", Context: "We need a cache.", Decision: "Adopt Redis"}
+	b := ADRRequest{Title: "use  redis", Context: "We need a\ncache.", Decision: "adopt redis ", Tags: []string{"x"}}
+	if a.ContentHash() != b.ContentHash() {
+		t.Error("hash differs across cosmetic edits")
+	}
+	if c := (ADRRequest{Title: "Use Redis", Context: "We need a cache.", Decision: "Adopt Memcached"}); c.ContentHash() == a.ContentHash() {
+		t.Error("hash ignores the decision")
+	}
+
+	a.DedupBy = DedupContentHash
+	body, err := json.Marshal(a.payload())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sent map[string]any
+	json.Unmarshal(body, &sent)
+	if sent["content_hash"] != a.ContentHash() || sent["dedup_by"] != "content_hash" || sent["title"] != "Use Redis" {
+		t.Errorf("body = %s", body)
+	}
+
+	a.DedupBy = "fuzzy"
+	if err := a.Validate(); err == nil {
+		t.Error("unknown dedup strategy accepted")
+	}
+}
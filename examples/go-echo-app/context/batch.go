@@ -0,0 +1,163 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const defaultBatchConcurrency = 4
+
+// WithBatchConcurrency bounds the number of in-flight requests when a batch
+// call falls back to client-side fan-out.
+func WithBatchConcurrency(n int) Option {
+	return func(c *Client) {
+		c.batchConcurrency = n
+	}
+}
+
+// BatchError reports the items of a batch call that failed, keyed by their
+// index in the input. Items not listed succeeded.
+type BatchError struct {
+	Errors map[int]error
+}
+
+func (e *BatchError) Error() string {
+	idx := e.indices()
+	parts := make([]string, 0, min(len(idx), 3))
+	for _, i := range idx[:min(len(idx), 3)] {
+		parts = append(parts, fmt.Sprintf("[%d] %v", i, e.Errors[i]))
+	}
+	if len(idx) > 3 {
+		parts = append(parts, fmt.Sprintf("and %d more", len(idx)-3))
+	}
+	return fmt.Sprintf("%d batch item(s) failed: %s", len(idx), strings.Join(parts, "; "))
+}
+
+func (e *BatchError) indices() []int {
+	idx := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+	return idx
+}
+
+func batchErr(errs map[int]error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &BatchError{Errors: errs}
+}
+
+// isUnsupported reports whether err means the server lacks the endpoint.
+func isUnsupported(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+// QueryBatch runs reqs in a single round trip and returns responses in the
+// same order. Servers without /context/query/batch are handled by issuing
+// the queries concurrently, bounded by WithBatchConcurrency; once
+// Capabilities or SupportsBatch has run, such servers are not tried first.
+// Per-query failures are reported through a *BatchError alongside the
+// responses that succeeded; failed indices hold a zero QueryResponse.
+func (c *Client) QueryBatch(ctx context.Context, reqs []QueryRequest, opts ...CallOption) ([]QueryResponse, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	if caps, ok := c.knownCapabilities(); ok && !caps.Batch {
+		return c.queryFanOut(ctx, reqs, opts)
+	}
+
+	var resp struct {
+		Results []struct {
+			Response *QueryResponse `json:"response"`
+			Error    string         `json:"error"`
+		} `json:"results"`
+	}
+	err := c.do(ctx, call{
+		op:     "QueryBatch",
+		method: http.MethodPost,
+		path:   "/context/query/batch",
+		in:     map[string]any{"queries": reqs},
+		out:    &resp,
+		opts:   opts,
+		read:   true,
+	})
+	if isUnsupported(err) {
+		return c.queryFanOut(ctx, reqs, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Results) != len(reqs) {
+		return nil, fmt.Errorf("query batch: got %d results for %d queries", len(resp.Results), len(reqs))
+	}
+
+	out := make([]QueryResponse, len(reqs))
+	errs := map[int]error{}
+	for i, r := range resp.Results {
+		switch {
+		case r.Error != "":
+			errs[i] = errors.New(r.Error)
+		case r.Response != nil:
+			out[i] = *r.Response
+		}
+	}
+	return out, batchErr(errs)
+}
+
+func (c *Client) queryFanOut(ctx context.Context, reqs []QueryRequest, opts []CallOption) ([]QueryResponse, error) {
+	out := make([]QueryResponse, len(reqs))
+	errs := c.fanOut(ctx, len(reqs), func(ctx context.Context, i int) error {
+		resp, err := c.Query(ctx, reqs[i], opts...)
+		if err != nil {
+			return err
+		}
+		out[i] = *resp
+		return nil
+	})
+	return out, batchErr(errs)
+}
+
+// fanOut runs fn for indices [0, n) with at most WithBatchConcurrency calls
+// in flight and collects the failures by index.
+func (c *Client) fanOut(ctx context.Context, n int, fn func(ctx context.Context, i int) error) map[int]error {
+	limit := c.batchConcurrency
+	if limit <= 0 {
+		limit = defaultBatchConcurrency
+	}
+	sem := make(chan struct{}, limit)
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = map[int]error{}
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			if err := fn(ctx, i); err != nil {
+				mu.Lock()
+				errs[i] = err
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,37 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQueryBatchFallsBackToFanOut(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/context/query/batch" {
+			http.NotFound(w, r)
+			return
+		}
+		var req QueryRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Query == "bad" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(QueryResponse{TotalItems: len(req.Query)})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithBatchConcurrency(2))
+	out, err := c.QueryBatch(context.Background(), []QueryRequest{{Query: "a"}, {Query: "bad"}, {Query: "ccc"}})
+	var be *BatchError
+	if !errors.As(err, &be) || len(be.Errors) != 1 || be.Errors[1] == nil {
+		t.Fatalf("err = %v, want a BatchError for index 1", err)
+	}
+	if out[0].TotalItems != 1 || out[2].TotalItems != 3 {
+		t.Errorf("out = %+v, want results in input order", out)
+	}
+}
@@ -0,0 +1,341 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// exportPageSize is how many records ExportBundle asks for per list call.
+const exportPageSize = 200
+
+// Bundle is a whole knowledge base as one JSON document, as written by
+// ExportBundle and read by ImportBundle.
+type Bundle struct {
+	ADRs     []BundleADR     `json:"adrs"`
+	Failures []BundleFailure `json:"failures"`
+	Changes  []ChangeRequest `json:"changes"`
+}
+
+// BundleADR is an ADR as exported, with the links and state that creating
+// it doesn't carry. ID is the ADR's ID on the exporting server; ImportBundle
+// maps it to the new ID so Supersedes and BundleFailure.RelatedADRs still
+// resolve.
+type BundleADR struct {
+	ADRRequest
+	ID         string `json:"id,omitempty"`
+	Supersedes string `json:"supersedes,omitempty"`
+	Deleted    bool   `json:"deleted,omitempty"`
+}
+
+// BundleFailure is a failure as exported, with the IDs of its related ADRs
+// as they were on the exporting server.
+type BundleFailure struct {
+	FailureRequest
+	RelatedADRs []string `json:"related_adrs,omitempty"`
+}
+
+// ImportOptions controls ImportBundle.
+type ImportOptions struct {
+	// SkipExisting skips records whose title, ignoring case and spacing,
+	// matches one already on the server of the same kind.
+	SkipExisting bool
+	// ContinueOnError imports the remaining records after one fails
+	// instead of stopping.
+	ContinueOnError bool
+}
+
+// ImportCounts tallies ImportBundle's outcome for one kind of record.
+type ImportCounts struct {
+	Created int
+	Skipped int
+	Failed  int
+}
+
+// ImportResult reports what ImportBundle did, per kind of record.
+type ImportResult struct {
+	ADRs     ImportCounts
+	Failures ImportCounts
+	Changes  ImportCounts
+}
+
+// ExportBundle writes every ADR, soft-deleted ones included, and every
+// failure and change on the server to w as a Bundle that ImportBundle can
+// load into another server. Lists are read a page at a time. Server-assigned
+// fields such as scores and timestamps are not exported.
+func (c *Client) ExportBundle(ctx context.Context, w io.Writer, opts ...CallOption) error {
+	var b Bundle
+
+	adrs, err := listAll(func(p page) ([]Decision, error) {
+		return c.ListADRs(ctx, ADRFilter{IncludeDeleted: true, page: p}, opts...)
+	}, func(d Decision) string { return d.ID })
+	if err != nil {
+		return err
+	}
+	for _, d := range adrs {
+		if len(d.OptionsConsideredOrdered) > 0 {
+			// The server returns both shapes; a request may carry only one.
+			d.OptionsConsidered = nil
+		}
+		b.ADRs = append(b.ADRs, BundleADR{
+			ADRRequest: ADRRequest{
+				Title:                    d.Title,
+				Decision:                 d.Decision,
+				Context:                  d.Context,
+				OptionsConsidered:        d.OptionsConsidered,
+				OptionsConsideredOrdered: d.OptionsConsideredOrdered,
+				Tags:                     d.Tags,
+				Stakeholders:             d.Stakeholders,
+				Owners:                   d.Owners,
+				Evidence:                 d.Evidence,
+				Visibility:               d.Visibility,
+			},
+			ID:         d.ID,
+			Supersedes: d.Supersedes,
+			Deleted:    !d.DeletedAt.IsZero(),
+		})
+	}
+
+	issues, err := listAll(func(p page) ([]Issue, error) {
+		return c.ListFailures(ctx, FailureFilter{page: p}, opts...)
+	}, func(i Issue) string { return i.ID })
+	if err != nil {
+		return err
+	}
+	for _, i := range issues {
+		b.Failures = append(b.Failures, BundleFailure{
+			FailureRequest: FailureRequest{
+				Title:      i.Title,
+				RootCause:  i.RootCause,
+				Symptoms:   i.Symptoms,
+				Impact:     i.Impact,
+				Resolution: i.Resolution,
+				Prevention: i.Prevention,
+				Runbook:    i.Runbook,
+				Severity:   i.Severity,
+				Pattern:    i.Pattern,
+				Tags:       i.Tags,
+				Timeline:   i.Timeline,
+			},
+			RelatedADRs: i.RelatedADRs,
+		})
+	}
+
+	changes, err := listAll(func(p page) ([]Change, error) {
+		return c.ListChanges(ctx, ChangeFilter{page: p}, opts...)
+	}, func(ch Change) string { return ch.ID })
+	if err != nil {
+		return err
+	}
+	for _, ch := range changes {
+		b.Changes = append(b.Changes, ChangeRequest{Type: ch.Type, Title: ch.Title, Description: ch.Description, Tags: ch.Tags})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// listAll calls list for successive pages until one comes back short. A
+// page that adds no new IDs also ends it, so a server that ignores the
+// paging parameters is read once rather than forever.
+func listAll[T any](list func(page) ([]T, error), id func(T) string) ([]T, error) {
+	var all []T
+	seen := map[string]bool{}
+	for p := (page{limit: exportPageSize}); ; p.offset += p.limit {
+		items, err := list(p)
+		if err != nil {
+			return nil, err
+		}
+		added := 0
+		for _, it := range items {
+			if k := id(it); !seen[k] {
+				seen[k] = true
+				all = append(all, it)
+				added++
+			}
+		}
+		if len(items) < p.limit || added == 0 {
+			return all, nil
+		}
+	}
+}
+
+// ImportBundle reads a Bundle from r and creates its ADRs, then its
+// failures, then its changes, one at a time. It then restores what creating
+// a record doesn't: supersession links, soft deletes and links from
+// failures to ADRs, translating exported ADR IDs to the new ones. Unless
+// opts.ContinueOnError is set it stops at the first step that fails; the
+// result counts what was done up to then. The returned error names each
+// failed record by its position in the bundle, e.g. "failures[3]".
+func (c *Client) ImportBundle(ctx context.Context, r io.Reader, opts ImportOptions, callOpts ...CallOption) (ImportResult, error) {
+	var res ImportResult
+	var b Bundle
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return res, fmt.Errorf("decode bundle: %w", err)
+	}
+
+	var existing struct{ adrs, failures, changes map[string]string }
+	if opts.SkipExisting {
+		adrs, err := c.ListADRs(ctx, ADRFilter{}, callOpts...)
+		if err != nil {
+			return res, err
+		}
+		issues, err := c.ListFailures(ctx, FailureFilter{}, callOpts...)
+		if err != nil {
+			return res, err
+		}
+		changes, err := c.ListChanges(ctx, ChangeFilter{}, callOpts...)
+		if err != nil {
+			return res, err
+		}
+		existing.adrs = titleIDs(adrs, func(d Decision) (string, string) { return d.Title, d.ID })
+		existing.failures = titleIDs(issues, func(i Issue) (string, string) { return i.Title, i.ID })
+		existing.changes = titleIDs(changes, func(ch Change) (string, string) { return ch.Title, ch.ID })
+	}
+
+	var errs []error
+	stop := func(err error) bool {
+		errs = append(errs, err)
+		return !opts.ContinueOnError
+	}
+
+	adrs, stopped := importAll(b.ADRs, "adrs", existing.adrs, &res.ADRs, stop,
+		func(rec BundleADR) string { return rec.Title },
+		func(rec BundleADR) (string, error) {
+			return single(c.CreateADRs(ctx, []ADRRequest{rec.ADRRequest}, callOpts...))
+		})
+	if stopped {
+		return res, errors.Join(errs...)
+	}
+	adrIDs := map[string]string{} // exported ID to ID on this server
+	for i, rec := range b.ADRs {
+		if rec.ID != "" && adrs[i].id != "" {
+			adrIDs[rec.ID] = adrs[i].id
+		}
+	}
+	newADRID := func(old string) (string, error) {
+		if id, ok := adrIDs[old]; ok {
+			return id, nil
+		}
+		return "", fmt.Errorf("ADR %s is not in the bundle", old)
+	}
+	for i, rec := range b.ADRs {
+		if !adrs[i].created || rec.Supersedes == "" {
+			continue
+		}
+		old, err := newADRID(rec.Supersedes)
+		if err == nil {
+			err = c.SupersedeADR(ctx, old, adrs[i].id, callOpts...)
+		}
+		if err != nil && stop(fmt.Errorf("adrs[%d]: supersede: %w", i, err)) {
+			return res, errors.Join(errs...)
+		}
+	}
+	// Deletes come after the links so superseding a deleted ADR works.
+	for i, rec := range b.ADRs {
+		if !adrs[i].created || !rec.Deleted {
+			continue
+		}
+		if err := c.DeleteADR(ctx, adrs[i].id, append(slices.Clip(callOpts), WithSoftDelete())...); err != nil && stop(fmt.Errorf("adrs[%d]: delete: %w", i, err)) {
+			return res, errors.Join(errs...)
+		}
+	}
+
+	// RecordFailures, not RecordFailure, so WithFailureBuffer can't hold
+	// records back and the new IDs are known.
+	failures, stopped := importAll(b.Failures, "failures", existing.failures, &res.Failures, stop,
+		func(rec BundleFailure) string { return rec.Title },
+		func(rec BundleFailure) (string, error) {
+			return single(c.RecordFailures(ctx, []FailureRequest{rec.FailureRequest}, callOpts...))
+		})
+	if stopped {
+		return res, errors.Join(errs...)
+	}
+	for i, rec := range b.Failures {
+		if !failures[i].created {
+			continue
+		}
+		for _, related := range rec.RelatedADRs {
+			adrID, err := newADRID(related)
+			if err == nil {
+				err = c.LinkFailureToADR(ctx, failures[i].id, adrID, callOpts...)
+			}
+			if err != nil && stop(fmt.Errorf("failures[%d]: link: %w", i, err)) {
+				return res, errors.Join(errs...)
+			}
+		}
+	}
+
+	importAll(b.Changes, "changes", existing.changes, &res.Changes, stop,
+		func(req ChangeRequest) string { return req.Title },
+		func(req ChangeRequest) (string, error) { return c.CreateChange(ctx, req, callOpts...) })
+	return res, errors.Join(errs...)
+}
+
+// imported is the outcome of importing one record: its ID on this server,
+// if it was created or found, and whether it was created.
+type imported struct {
+	id      string
+	created bool
+}
+
+// importAll creates each record not in existing, tallying into counts, and
+// returns each record's outcome. It reports whether stop asked it to give
+// up after a failure.
+func importAll[T any](recs []T, name string, existing map[string]string, counts *ImportCounts,
+	stop func(error) bool, title func(T) string, create func(T) (string, error)) ([]imported, bool) {
+	out := make([]imported, len(recs))
+	for i, rec := range recs {
+		key := normalizeText(title(rec))
+		if id, ok := existing[key]; ok {
+			out[i].id = id
+			counts.Skipped++
+			continue
+		}
+		id, err := create(rec)
+		if err != nil {
+			counts.Failed++
+			if stop(fmt.Errorf("%s[%d]: %w", name, i, err)) {
+				return out, true
+			}
+			continue
+		}
+		out[i] = imported{id: id, created: true}
+		counts.Created++
+		if existing != nil {
+			// A bundle that repeats a record imports it once.
+			existing[key] = id
+		}
+	}
+	return out, false
+}
+
+// single unwraps the result of a one-record bulk call.
+func single(ids []string, err error) (string, error) {
+	var be *BatchError
+	if errors.As(err, &be) {
+		if e, ok := be.Errors[0]; ok {
+			return "", e
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(ids) == 0 {
+		return "", errors.New("server returned no ID")
+	}
+	return ids[0], nil
+}
+
+func titleIDs[T any](items []T, key func(T) (string, string)) map[string]string {
+	m := make(map[string]string, len(items))
+	for _, it := range items {
+		title, id := key(it)
+		m[normalizeText(title)] = id
+	}
+	return m
+}
@@ -0,0 +1,266 @@
+package context
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// bundleServer is an in-memory store for the endpoints ExportBundle and
+// ImportBundle use. Lists honour limit and offset.
+type bundleServer struct {
+	mu       sync.Mutex
+	next     int
+	adrs     []Decision
+	failures []Issue
+	changes  []Change
+}
+
+func (s *bundleServer) id(kind string) string {
+	s.next++
+	return fmt.Sprintf("%s-%d", kind, s.next)
+}
+
+func (s *bundleServer) adr(id string) *Decision {
+	for i := range s.adrs {
+		if s.adrs[i].ID == id {
+			return &s.adrs[i]
+		}
+	}
+	return nil
+}
+
+func pageOf[T any](r *http.Request, items []T) []T {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if limit == 0 {
+		return items
+	}
+	return items[min(offset, len(items)):min(offset+limit, len(items))]
+}
+
+func (s *bundleServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	var out any = struct{}{}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/adr":
+		var adrs []Decision
+		for _, d := range s.adrs {
+			if d.DeletedAt.IsZero() || r.URL.Query().Get("include_deleted") == "true" {
+				adrs = append(adrs, d)
+			}
+		}
+		out = pageOf(r, adrs)
+	case r.Method == http.MethodGet && r.URL.Path == "/failure":
+		out = pageOf(r, s.failures)
+	case r.Method == http.MethodGet && r.URL.Path == "/changes":
+		out = pageOf(r, s.changes)
+	case r.URL.Path == "/adr/bulk":
+		var req struct{ ADRs []Decision }
+		json.NewDecoder(r.Body).Decode(&req)
+		var ids []string
+		for _, d := range req.ADRs {
+			d.ID = s.id("adr")
+			s.adrs = append(s.adrs, d)
+			ids = append(ids, d.ID)
+		}
+		out = map[string][]string{"ids": ids}
+	case len(parts) == 3 && parts[2] == "supersede":
+		var req struct {
+			SupersededBy string `json:"superseded_by"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		s.adr(parts[1]).SupersededBy = req.SupersededBy
+		s.adr(req.SupersededBy).Supersedes = parts[1]
+	case r.Method == http.MethodDelete && len(parts) == 2:
+		s.adr(parts[1]).DeletedAt = time.Now()
+	case r.URL.Path == "/failure/bulk":
+		var req struct{ Failures []FailureRequest }
+		json.NewDecoder(r.Body).Decode(&req)
+		var ids []string
+		for _, f := range req.Failures {
+			id := s.id("failure")
+			s.failures = append(s.failures, Issue{
+				ID: id, Title: f.Title, RootCause: f.RootCause, Symptoms: f.Symptoms, Impact: f.Impact,
+				Resolution: f.Resolution, Prevention: f.Prevention, Runbook: f.Runbook, Severity: f.Severity,
+				Pattern: f.Pattern, Tags: f.Tags, Timeline: f.Timeline,
+			})
+			ids = append(ids, id)
+		}
+		out = map[string][]string{"ids": ids}
+	case len(parts) == 3 && parts[2] == "adrs":
+		var req struct {
+			ADRID string `json:"adr_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		for i := range s.failures {
+			if s.failures[i].ID == parts[1] {
+				s.failures[i].RelatedADRs = append(s.failures[i].RelatedADRs, req.ADRID)
+			}
+		}
+	case r.URL.Path == "/changes":
+		var req ChangeRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		ch := Change{ID: s.id("change"), Type: req.Type, Title: req.Title, Description: req.Description, Tags: req.Tags}
+		s.changes = append(s.changes, ch)
+		out = map[string]string{"id": ch.ID}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	json.NewEncoder(w).Encode(out)
+}
+
+// exportBundle exports from s and decodes the result, with each ADR ID
+// replaced by the ADR's title so bundles from different servers compare.
+func exportBundle(t *testing.T, s *bundleServer) Bundle {
+	t.Helper()
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	var buf bytes.Buffer
+	if err := NewClient(srv.URL).ExportBundle(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	var b Bundle
+	if err := json.Unmarshal(buf.Bytes(), &b); err != nil {
+		t.Fatal(err)
+	}
+	titles := map[string]string{}
+	for _, a := range b.ADRs {
+		titles[a.ID] = a.Title
+	}
+	for i := range b.ADRs {
+		b.ADRs[i].ID = titles[b.ADRs[i].ID]
+		b.ADRs[i].Supersedes = titles[b.ADRs[i].Supersedes]
+	}
+	for i := range b.Failures {
+		for j, id := range b.Failures[i].RelatedADRs {
+			b.Failures[i].RelatedADRs[j] = titles[id]
+		}
+	}
+	return b
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	src := &bundleServer{
+		adrs: []Decision{
+			{
+				ID: "a1", Title: "Use Echo", Decision: "Echo v4", Context: "need a router",
+				OptionsConsidered:        map[string][]string{"echo": {"fast"}, "gin": {"popular"}},
+				OptionsConsideredOrdered: []ConsideredOption{{Name: "echo", Pros: []string{"fast"}}, {Name: "gin", Pros: []string{"popular"}}},
+				Tags:                     []string{"http"}, Stakeholders: []string{"api"}, Owners: []string{"@web"},
+				Evidence:     []EvidenceRef{{Type: "benchmark", Ref: "bench-1"}},
+				Visibility:   VisibilityTeam,
+				SupersededBy: "a2",
+			},
+			{ID: "a2", Title: "Use Echo v5", Decision: "Echo v5", Tags: []string{}, Supersedes: "a1"},
+			{ID: "a3", Title: "Use MySQL", Decision: "MySQL", Tags: []string{}, DeletedAt: at},
+		},
+		failures: []Issue{
+			{
+				ID: "f1", Title: "Pool exhausted", RootCause: "leak", Symptoms: "timeouts", Impact: "checkout down",
+				Resolution: "restart", Prevention: []string{"pool metrics"}, Runbook: "https://runbooks/pool",
+				Severity: SeverityCritical, Pattern: PatternDatabaseError, Tags: []string{"db"},
+				Timeline: []TimelineEvent{{At: at, Note: "paged"}}, RelatedADRs: []string{"a1", "a3"},
+			},
+			{ID: "f2", Title: "Slow login", RootCause: "n+1", Severity: SeverityLow, Tags: []string{}},
+		},
+	}
+	for i := 0; i < exportPageSize+5; i++ {
+		src.changes = append(src.changes, Change{
+			ID: fmt.Sprintf("c%d", i), Type: ChangeFeature, Title: fmt.Sprintf("v1.%d", i), Description: "notes", Tags: []string{},
+		})
+	}
+	want := exportBundle(t, src)
+	if len(want.ADRs) != 3 || len(want.Failures) != 2 || len(want.Changes) != exportPageSize+5 {
+		t.Fatalf("exported %d ADRs, %d failures, %d changes", len(want.ADRs), len(want.Failures), len(want.Changes))
+	}
+
+	var raw bytes.Buffer
+	srcSrv := httptest.NewServer(src)
+	defer srcSrv.Close()
+	if err := NewClient(srcSrv.URL).ExportBundle(context.Background(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	dst := &bundleServer{}
+	dstSrv := httptest.NewServer(dst)
+	defer dstSrv.Close()
+	// Buffered failures must still be created before ImportBundle returns.
+	c := NewClient(dstSrv.URL, WithFailureBuffer(100, time.Hour))
+	defer c.Close(context.Background())
+	res, err := c.ImportBundle(context.Background(), &raw, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (ImportResult{ADRs: ImportCounts{Created: 3}, Failures: ImportCounts{Created: 2}, Changes: ImportCounts{Created: exportPageSize + 5}}) {
+		t.Errorf("result = %+v", res)
+	}
+
+	if got := exportBundle(t, dst); !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", " ")
+		wantJSON, _ := json.MarshalIndent(want, "", " ")
+		t.Errorf("round trip differs:\ngot  %s\nwant %s", gotJSON, wantJSON)
+	}
+}
+
+func TestExportBundleKeepsEverySeverity(t *testing.T) {
+	b := exportBundle(t, &bundleServer{failures: []Issue{
+		{ID: "f1", Title: "a", Severity: "sev0"},
+		{ID: "f2", Title: "b"},
+	}})
+	if len(b.Failures) != 2 || b.Failures[0].Severity != "sev0" || b.Failures[1].Severity != "" {
+		t.Errorf("failures = %+v", b.Failures)
+	}
+}
+
+func TestImportBundleSkipAndStop(t *testing.T) {
+	bundle := `{"adrs":[{"id":"a1","title":"Use Echo","decision":"d"},{"id":"a2","title":"Use GORM","decision":"d","supersedes":"a1"}],
+		"failures":[{"title":"Pool exhausted","root_cause":"leak","severity":"high","related_adrs":["a1"]}]}`
+	dst := &bundleServer{adrs: []Decision{{ID: "x", Title: "use  echo", Decision: "d"}}}
+	srv := httptest.NewServer(dst)
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	res, err := c.ImportBundle(context.Background(), strings.NewReader(bundle), ImportOptions{SkipExisting: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (ImportResult{ADRs: ImportCounts{Created: 1, Skipped: 1}, Failures: ImportCounts{Created: 1}}) {
+		t.Errorf("result = %+v", res)
+	}
+	// Links to a skipped ADR resolve to the one already on the server.
+	if dst.adrs[1].Supersedes != "x" || !reflect.DeepEqual(dst.failures[0].RelatedADRs, []string{"x"}) {
+		t.Errorf("links: adr %+v, failure %+v", dst.adrs[1], dst.failures[0])
+	}
+
+	// An invalid record stops the import unless ContinueOnError is set.
+	bad := `{"adrs":[{"title":"no decision"},{"title":"t2","decision":"d"}]}`
+	res, err = c.ImportBundle(context.Background(), strings.NewReader(bad), ImportOptions{})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || !strings.Contains(err.Error(), "adrs[0]") || res.ADRs != (ImportCounts{Failed: 1}) {
+		t.Errorf("stop: res = %+v, err = %v", res, err)
+	}
+	res, err = c.ImportBundle(context.Background(), strings.NewReader(bad), ImportOptions{ContinueOnError: true})
+	if err == nil || res.ADRs != (ImportCounts{Created: 1, Failed: 1}) {
+		t.Errorf("continue: res = %+v, err = %v", res, err)
+	}
+
+	// A link to an ADR missing from the bundle is reported.
+	orphan := `{"failures":[{"title":"f","root_cause":"r","severity":"low","related_adrs":["gone"]}]}`
+	if _, err := c.ImportBundle(context.Background(), strings.NewReader(orphan), ImportOptions{}); err == nil || !strings.Contains(err.Error(), "failures[0]: link") {
+		t.Errorf("orphan link: err = %v", err)
+	}
+}
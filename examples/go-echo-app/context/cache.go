@@ -0,0 +1,181 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the query cache; the oldest entries are evicted
+// first once it is full.
+const maxCacheEntries = 1000
+
+// WithQueryCache caches Query responses for ttl, keyed by the full request.
+// Writes do not invalidate it, so pick a ttl the callers can tolerate
+// seeing stale results for. Callers get their own copy of each response.
+func WithQueryCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = &queryCache{
+			ttl:        ttl,
+			entries:    make(map[string]cacheEntry),
+			refreshing: make(map[string]bool),
+		}
+	}
+}
+
+// WithCacheRefreshAhead makes Query refresh a cached response in the
+// background once it is older than fraction of the cache ttl, still
+// answering from the cache meanwhile, so hot entries never expire under a
+// caller. Only one refresh per entry runs at a time. It requires
+// WithQueryCache; fraction must be in (0, 1) to have any effect.
+func WithCacheRefreshAhead(fraction float64) Option {
+	return func(c *Client) {
+		c.refreshAhead = fraction
+	}
+}
+
+type queryCache struct {
+	ttl time.Duration
+
+	mu         sync.Mutex
+	entries    map[string]cacheEntry
+	refreshing map[string]bool
+}
+
+type cacheEntry struct {
+	resp   *QueryResponse
+	stored time.Time
+}
+
+func cacheKey(req QueryRequest) string {
+	// SortBy and friends travel in the URL, not the body.
+	b, _ := json.Marshal(req)
+	return string(b) + "?" + req.values().Encode()
+}
+
+// get returns the cached response for key and how long ago it was stored.
+func (q *queryCache) get(key string) (*QueryResponse, time.Duration, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	e, ok := q.entries[key]
+	if !ok {
+		return nil, 0, false
+	}
+	age := time.Since(e.stored)
+	if age >= q.ttl {
+		delete(q.entries, key)
+		return nil, 0, false
+	}
+	return e.resp.clone(), age, true
+}
+
+// claim marks key as being refreshed. It returns false if a refresh is
+// already running; otherwise the caller must call release when done.
+func (q *queryCache) claim(key string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.refreshing[key] {
+		return false
+	}
+	q.refreshing[key] = true
+	return true
+}
+
+func (q *queryCache) release(key string) {
+	q.mu.Lock()
+	delete(q.refreshing, key)
+	q.mu.Unlock()
+}
+
+// cachedQuery answers req from the cache, starting a background refresh
+// if the entry is due for one.
+func (c *Client) cachedQuery(req QueryRequest, opts []CallOption) (*QueryResponse, bool) {
+	key := cacheKey(req)
+	resp, age, ok := c.cache.get(key)
+	if c.metrics != nil {
+		c.metrics.ObserveCache("Query", ok)
+	}
+	if !ok {
+		return nil, false
+	}
+	due := c.refreshAhead > 0 && c.refreshAhead < 1 &&
+		age >= time.Duration(c.refreshAhead*float64(c.cache.ttl))
+	if due && c.cache.claim(key) {
+		started := c.goBackground(func() {
+			defer c.cache.release(key)
+			// Not tied to the caller's context, which may end first, but
+			// stopped by Close.
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				select {
+				case <-c.done:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+			// The caller has its answer; don't write to its ResponseMeta.
+			_, _ = c.fetchQuery(ctx, req, append(opts[:len(opts):len(opts)], WithResponseMeta(nil)))
+		})
+		if !started {
+			c.cache.release(key)
+		}
+	}
+	return resp, true
+}
+
+func (q *queryCache) put(key string, resp *QueryResponse) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.entries[key]; !ok && len(q.entries) >= maxCacheEntries {
+		q.evict()
+	}
+	q.entries[key] = cacheEntry{resp: resp.clone(), stored: time.Now()}
+}
+
+// evict drops expired entries, or the oldest one if none have expired.
+func (q *queryCache) evict() {
+	var oldest string
+	var oldestAt time.Time
+	for k, e := range q.entries {
+		if time.Since(e.stored) >= q.ttl {
+			delete(q.entries, k)
+			continue
+		}
+		if oldest == "" || e.stored.Before(oldestAt) {
+			oldest, oldestAt = k, e.stored
+		}
+	}
+	if len(q.entries) >= maxCacheEntries {
+		delete(q.entries, oldest)
+	}
+}
+
+// clone copies r's slices so cached responses can't be changed through
+// the copies handed to callers.
+func (r *QueryResponse) clone() *QueryResponse {
+	out := *r
+	out.KeyDecisions = append([]Decision(nil), r.KeyDecisions...)
+	out.KnownIssues = append([]Issue(nil), r.KnownIssues...)
+	out.RecentChanges = append([]Change(nil), r.RecentChanges...)
+	out.Facets = maps.Clone(r.Facets)
+	return &out
+}
+
+// WarmCache runs queries concurrently, bounded by WithBatchConcurrency, and
+// stores the results in the query cache, replacing any cached entries. A
+// failed query doesn't stop the others; failures are reported through a
+// *BatchError keyed by index. It requires WithQueryCache.
+func (c *Client) WarmCache(ctx context.Context, queries []QueryRequest, opts ...CallOption) error {
+	if c.cache == nil {
+		return errors.New("warm cache: query cache not enabled")
+	}
+	errs := c.fanOut(ctx, len(queries), func(ctx context.Context, i int) error {
+		_, err := c.fetchQuery(ctx, queries[i], opts)
+		return err
+	})
+	return batchErr(errs)
+}
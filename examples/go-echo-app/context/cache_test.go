@@ -0,0 +1,133 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueryCache(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var req QueryRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Query == "bad" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(QueryResponse{KeyDecisions: []Decision{{ID: req.Query}}})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithQueryCache(time.Minute), WithRetry(RetryPolicy{MaxAttempts: 1}))
+	err := c.WarmCache(context.Background(), []QueryRequest{{Query: "a"}, {Query: "bad"}, {Query: "b"}})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || batchErr.Errors[1] == nil {
+		t.Fatalf("WarmCache err = %v", err)
+	}
+
+	before := calls.Load()
+	resp, err := c.Query(context.Background(), QueryRequest{Query: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != before || resp.KeyDecisions[0].ID != "a" {
+		t.Errorf("cache miss: calls %d -> %d, resp = %+v", before, calls.Load(), resp)
+	}
+	resp.KeyDecisions[0].ID = "changed"
+	if again, _ := c.Query(context.Background(), QueryRequest{Query: "a"}); again.KeyDecisions[0].ID != "a" {
+		t.Error("cached response was modified through a returned copy")
+	}
+	if _, err := c.Query(context.Background(), QueryRequest{Query: "c"}); err != nil || calls.Load() != before+1 {
+		t.Errorf("uncached query: err = %v, calls = %d", err, calls.Load())
+	}
+}
+
+func TestCacheRefreshAhead(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(QueryResponse{TotalItems: int(calls.Add(1))})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithQueryCache(time.Hour), WithCacheRefreshAhead(0.5))
+	defer c.Close(context.Background())
+	req := QueryRequest{Query: "q"}
+	if _, err := c.Query(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	// Age the entry past the refresh point but not past the ttl.
+	key := cacheKey(req)
+	c.cache.mu.Lock()
+	e := c.cache.entries[key]
+	e.stored = e.stored.Add(-45 * time.Minute)
+	c.cache.entries[key] = e
+	c.cache.mu.Unlock()
+
+	for i := 0; i < 5; i++ {
+		resp, err := c.Query(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.TotalItems != 1 {
+			t.Fatalf("stale hit returned %d", resp.TotalItems)
+		}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, _ := c.Query(context.Background(), req)
+		if resp.TotalItems == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("entry was not refreshed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("server calls = %d, want one refresh", n)
+	}
+}
+
+func TestCacheRefreshAheadAfterClose(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		json.NewEncoder(w).Encode(QueryResponse{})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithQueryCache(time.Hour), WithCacheRefreshAhead(0.5))
+	req := QueryRequest{Query: "q"}
+	if _, err := c.Query(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	c.cache.mu.Lock()
+	e := c.cache.entries[cacheKey(req)]
+	e.stored = e.stored.Add(-45 * time.Minute)
+	c.cache.entries[cacheKey(req)] = e
+	c.cache.mu.Unlock()
+
+	c.Close(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Query(context.Background(), req)
+		}()
+	}
+	wg.Wait()
+	c.Close(context.Background())
+	if n := calls.Load(); n != 1 {
+		t.Errorf("refreshed after Close: %d calls", n)
+	}
+}
@@ -0,0 +1,65 @@
+package context
+
+import (
+	"context"
+	"net/http"
+)
+
+// Capabilities describes what a context engine supports.
+type Capabilities struct {
+	Version      string `json:"version"`
+	Batch        bool   `json:"batch"`
+	Facets       bool   `json:"facets"`
+	Streaming    bool   `json:"streaming"`
+	Transactions bool   `json:"transactions"`
+	DryRun       bool   `json:"dry_run"`
+}
+
+// Capabilities fetches the server's capabilities and caches them for
+// SupportsBatch, QueryBatch and WithDryRun.
+func (c *Client) Capabilities(ctx context.Context, opts ...CallOption) (Capabilities, error) {
+	var caps Capabilities
+	err := c.do(ctx, call{
+		op:     "Capabilities",
+		method: http.MethodGet,
+		path:   "/capabilities",
+		out:    &caps,
+		opts:   opts,
+	})
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	c.capsMu.Lock()
+	c.caps = &caps
+	c.capsMu.Unlock()
+	return caps, nil
+}
+
+// SupportsBatch reports whether the server has /context/query/batch,
+// fetching its capabilities on first use. Servers too old to advertise
+// capabilities are reported as not supporting batches; that answer is
+// cached as well.
+func (c *Client) SupportsBatch(ctx context.Context) (bool, error) {
+	if caps, ok := c.knownCapabilities(); ok {
+		return caps.Batch, nil
+	}
+	caps, err := c.Capabilities(ctx)
+	if isUnsupported(err) {
+		c.capsMu.Lock()
+		c.caps = &Capabilities{}
+		c.capsMu.Unlock()
+		return false, nil
+	}
+	return caps.Batch, err
+}
+
+// knownCapabilities returns the cached capabilities without fetching them.
+func (c *Client) knownCapabilities() (Capabilities, bool) {
+	c.capsMu.Lock()
+	defer c.capsMu.Unlock()
+	if c.caps == nil {
+		return Capabilities{}, false
+	}
+	return *c.caps, true
+}
@@ -0,0 +1,46 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSupportsBatch(t *testing.T) {
+	var capsCalls, batchCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/capabilities":
+			capsCalls.Add(1)
+			w.Write([]byte(`{"version":"1.4.0","batch":false,"facets":true}`))
+		case "/context/query/batch":
+			batchCalls.Add(1)
+			http.NotFound(w, r)
+		default:
+			json.NewEncoder(w).Encode(QueryResponse{TotalItems: 1})
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	for i := 0; i < 2; i++ {
+		ok, err := c.SupportsBatch(context.Background())
+		if err != nil || ok {
+			t.Fatalf("SupportsBatch = %v, %v", ok, err)
+		}
+	}
+	if n := capsCalls.Load(); n != 1 {
+		t.Errorf("capabilities fetched %d times", n)
+	}
+
+	resps, err := c.QueryBatch(context.Background(), []QueryRequest{{Query: "a"}, {Query: "b"}})
+	if err != nil || len(resps) != 2 {
+		t.Fatalf("QueryBatch = %v, %v", resps, err)
+	}
+	if n := batchCalls.Load(); n != 0 {
+		t.Errorf("batch endpoint tried %d times", n)
+	}
+}
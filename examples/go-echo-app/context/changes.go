@@ -0,0 +1,114 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ChangeType classifies a Change. Servers may report other values, such as
+// the "model.create" changes recorded by contextgorm.
+type ChangeType string
+
+const (
+	ChangeFeature     ChangeType = "feature"
+	ChangeFix         ChangeType = "fix"
+	ChangeRefactor    ChangeType = "refactor"
+	ChangeDeprecation ChangeType = "deprecation"
+)
+
+// ChangeFilter narrows ListChanges and StreamChanges. Zero fields are not
+// sent. Until applies to ListChanges only; a stream has no end.
+type ChangeFilter struct {
+	Types []ChangeType
+	Tags  []string
+	Since time.Time
+	Until time.Time
+
+	page
+}
+
+// Validate reports an inverted Since/Until range.
+func (f ChangeFilter) Validate() error {
+	v := newValidator("ChangeFilter")
+	v.timeRange("since", "until", f.Since, f.Until)
+	return v.err()
+}
+
+func (f ChangeFilter) values() url.Values {
+	q := url.Values{}
+	if len(f.Types) > 0 {
+		types := make([]string, len(f.Types))
+		for i, t := range f.Types {
+			types[i] = string(t)
+		}
+		q.Set("types", strings.Join(types, ","))
+	}
+	if len(f.Tags) > 0 {
+		q.Set("tags", strings.Join(f.Tags, ","))
+	}
+	if !f.Since.IsZero() {
+		q.Set("since", f.Since.UTC().Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		q.Set("until", f.Until.UTC().Format(time.RFC3339))
+	}
+	f.page.set(q)
+	return q
+}
+
+// ChangeRequest records a change such as a feature, fix or deploy.
+type ChangeRequest struct {
+	Type        ChangeType `json:"type"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+}
+
+// CreateChange records req and returns its ID.
+func (c *Client) CreateChange(ctx context.Context, req ChangeRequest, opts ...CallOption) (string, error) {
+	if err := req.Validate(); err != nil {
+		return "", err
+	}
+	req.Tags = c.tags(req.Tags)
+	var resp struct {
+		ID string `json:"id"`
+	}
+	err := c.do(ctx, call{
+		op:     "CreateChange",
+		method: http.MethodPost,
+		path:   "/changes",
+		in:     req,
+		out:    &resp,
+		opts:   opts,
+		keyed:  true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+func (c *Client) ListChanges(ctx context.Context, filter ChangeFilter, opts ...CallOption) ([]Change, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	filter.Tags = c.tags(filter.Tags)
+	var changes []Change
+	err := c.do(ctx, call{
+		op:     "ListChanges",
+		method: http.MethodGet,
+		path:   "/changes",
+		query:  filter.values(),
+		out:    &changes,
+		opts:   opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
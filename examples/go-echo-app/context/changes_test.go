@@ -0,0 +1,67 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListChangesFilter(t *testing.T) {
+	var got url.Values
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		got = r.URL.Query()
+		w.Write([]byte(`[{"id":"c1","type":"fix","title":"t"}]`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	est := time.FixedZone("EST", -5*3600)
+	changes, err := c.ListChanges(context.Background(), ChangeFilter{
+		Types: []ChangeType{ChangeFix, "model.create"},
+		Since: time.Date(2026, 3, 1, 7, 0, 0, 0, est),
+		Until: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Type != ChangeFix {
+		t.Errorf("changes = %+v", changes)
+	}
+	if got.Get("types") != "fix,model.create" || got.Get("since") != "2026-03-01T12:00:00Z" || got.Get("until") != "2026-03-02T00:00:00Z" {
+		t.Errorf("query = %v", got)
+	}
+
+	// Only the set end of an open range is sent.
+	if _, err := c.ListChanges(context.Background(), ChangeFilter{Until: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got["since"]; ok || got.Get("until") == "" {
+		t.Errorf("open range query = %v", got)
+	}
+
+	var ve *ValidationError
+	inverted := ChangeFilter{Since: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Until: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	if _, err := c.ListChanges(context.Background(), inverted); !errors.As(err, &ve) || requests != 2 {
+		t.Errorf("inverted range: err = %v after %d requests", err, requests)
+	}
+}
+
+func TestChangeRequestType(t *testing.T) {
+	var ve *ValidationError
+	if err := (ChangeRequest{Title: "t"}).Validate(); !errors.As(err, &ve) || strings.Join(ve.Missing(), ",") != "type" {
+		t.Errorf("no type: err = %v", err)
+	}
+	// Types beyond the constants are accepted.
+	for _, typ := range []ChangeType{ChangeFeature, ChangeDeprecation, "model.create"} {
+		if err := (ChangeRequest{Type: typ, Title: "t"}).Validate(); err != nil {
+			t.Errorf("%s: %v", typ, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// SyncOp is the kind of mutation a SyncEntry records.
+type SyncOp string
+
+const (
+	SyncCreate SyncOp = "create"
+	SyncUpdate SyncOp = "update"
+	SyncDelete SyncOp = "delete"
+)
+
+// SyncEntry is one mutation in the store. Deletes are tombstones: they carry
+// the Kind and ID of the removed item but no Data.
+type SyncEntry struct {
+	Kind Kind            `json:"kind"`
+	ID   string          `json:"id"`
+	Op   SyncOp          `json:"op"`
+	At   time.Time       `json:"at"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Deleted reports whether e is a tombstone.
+func (e SyncEntry) Deleted() bool { return e.Op == SyncDelete }
+
+// Decode unmarshals the entity snapshot into v, typically a *Decision,
+// *Issue or *Change according to Kind.
+func (e SyncEntry) Decode(v any) error {
+	if len(e.Data) == 0 {
+		return fmt.Errorf("sync entry %s/%s has no data", e.Kind, e.ID)
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+// ChangeBatch is one page of a sync. Store Cursor and pass it to the next
+// SyncChanges call; HasMore means another page is immediately available.
+type ChangeBatch struct {
+	Entries []SyncEntry `json:"entries"`
+	Cursor  string      `json:"cursor"`
+	HasMore bool        `json:"has_more"`
+}
+
+// SyncChanges returns the creates, updates and deletes recorded after
+// sinceCursor, oldest first. An empty cursor starts from the beginning.
+func (c *Client) SyncChanges(ctx context.Context, sinceCursor string, opts ...CallOption) (*ChangeBatch, error) {
+	q := url.Values{}
+	if sinceCursor != "" {
+		q.Set("cursor", sinceCursor)
+	}
+
+	var batch ChangeBatch
+	err := c.do(ctx, call{
+		op:     "SyncChanges",
+		method: http.MethodGet,
+		path:   "/sync",
+		query:  q,
+		out:    &batch,
+		opts:   opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.Cursor == "" {
+		batch.Cursor = sinceCursor
+	}
+	return &batch, nil
+}
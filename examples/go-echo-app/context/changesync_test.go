@@ -0,0 +1,59 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSyncChanges(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"entries":[{"kind":"decision","id":"adr-1","op":"create","data":{"id":"adr-1","title":"Use Echo"}}],"cursor":"c1","has_more":true}`,
+		"c1": `{"entries":[{"kind":"decision","id":"adr-1","op":"delete"}],"cursor":"c2"}`,
+		// Nothing new: the server sends no cursor.
+		"c2": `{"entries":[]}`,
+	}
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cur := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cur)
+		w.Write([]byte(pages[cur]))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	var entries []SyncEntry
+	cursor := ""
+	for {
+		batch, err := c.SyncChanges(context.Background(), cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, batch.Entries...)
+		cursor = batch.Cursor
+		if !batch.HasMore {
+			break
+		}
+	}
+	if cursor != "c2" || len(entries) != 2 {
+		t.Fatalf("cursor = %q after %d entries", cursor, len(entries))
+	}
+	var d Decision
+	if err := entries[0].Decode(&d); err != nil || d.Title != "Use Echo" {
+		t.Errorf("create entry = %+v, %v", d, err)
+	}
+	if !entries[1].Deleted() || entries[1].Decode(&d) == nil {
+		t.Errorf("delete entry = %+v", entries[1])
+	}
+
+	// Resuming from the stored cursor keeps it when nothing is new.
+	batch, err := c.SyncChanges(context.Background(), cursor)
+	if err != nil || batch.Cursor != "c2" || len(batch.Entries) != 0 {
+		t.Errorf("resume = %+v, %v", batch, err)
+	}
+	if got := strings.Join(cursors, ","); got != ",c1,c2" {
+		t.Errorf("cursors sent = %q", got)
+	}
+}
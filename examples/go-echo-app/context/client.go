@@ -1,29 +1,516 @@
 package context
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
 type Client struct {
 	BaseURL string
 	client  *http.Client
+	header  http.Header
+	metrics MetricsRecorder
+	logger  func(RequestInfo)
+
+	slowThreshold time.Duration
+	slowLogger    func(RequestInfo)
+
+	decodeBufferSize int
+	strictDecoding   bool
+	compressMin      int
+	maxResponseBytes int64
+	visibility       Visibility
+	retry            RetryPolicy
+	idempotencyKey   func() string
+	requestID        func() string
+	batchConcurrency int
+
+	confidenceThreshold float64
+
+	domainsMu       sync.RWMutex
+	domains         map[string]struct{}
+	validateDomains bool
+
+	capsMu sync.Mutex
+	caps   *Capabilities
+
+	queue     *writeQueue
+	failures  *failureBuffer
+	endpoints []*endpoint
+	stats     stats
+	redactor  func(string) string
+
+	tokenBudget bool
+	dedup       bool
+
+	cache        *queryCache
+	refreshAhead float64
+
+	customClient *http.Client
+	timeout      time.Duration // per call unless WithCallTimeout overrides it
+	now          func() time.Time
+	unixSocket   string
+	pool         *connPool
+	dnsCache     *dnsCache
+
+	hedgeDelay time.Duration
+	adaptive   *adaptiveTimeout
+	codec      Codec
+
+	embeddingDim int
+	embedder     Embedder
+
+	tagNormalizer func(string) string
+	wsDialer      WebSocketDialer
+
+	// done is closed by Close to stop background goroutines, tracked by wg.
+	// closeMu orders starting a goroutine after construction against Close.
+	done    chan struct{}
+	closeMu sync.Mutex
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// Option configures a Client at construction time.
+type Option func(*Client)
+
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		header:  make(http.Header),
+
+		decodeBufferSize: defaultDecodeBufferSize,
+		maxResponseBytes: defaultMaxResponseBytes,
+		redactor:         DefaultRedactor,
+		codec:            JSON,
+		idempotencyKey:   newUUID,
+		requestID:        newUUID,
+		embedder:         noEmbedder{},
+
+		confidenceThreshold: defaultConfidenceThreshold,
+		timeout:             defaultTimeout,
+		now:                 time.Now,
+
+		done: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.client = c.httpClient()
+	if c.queue != nil {
+		c.wg.Add(1)
+		go c.replayLoop()
+	}
+	if c.failures != nil {
+		c.wg.Add(1)
+		go c.failureFlushLoop()
+	}
+	return c
+}
+
+// ErrClientClosed is returned once Close has been called by writes that
+// would otherwise be buffered or queued and never sent: RecordFailure with
+// WithFailureBuffer, and CreateADR, RecordFailure and RecordSuccess with
+// WithWriteQueue.
+var ErrClientClosed = errors.New("client is closed")
+
+// Close shuts the client down for good. It stops background goroutines
+// and waits for them, sends any failures held by WithFailureBuffer, replays
+// the WithWriteQueue queue best-effort, and closes idle connections. It
+// gives up when ctx is done; the returned error then says what was left
+// undone, so callers can retry with a longer deadline. Writes still queued
+// stay on disk for the next client. Close is safe to call more than once;
+// see ErrClientClosed for writes made after it.
+func (c *Client) Close(ctx context.Context) error {
+	c.closeMu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.done)
+	}
+	c.closeMu.Unlock()
+	defer c.client.CloseIdleConnections()
+
+	stopped := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		return fmt.Errorf("close: background work still running: %w", ctx.Err())
+	}
+
+	var errs []error
+	if c.failures != nil {
+		if err := c.flushAllFailures(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("close: flush failures: %w", err))
+		}
+	}
+	if err := c.Flush(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("close: replay write queue: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Client) isClosed() bool {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+	return c.closed
+}
+
+// goBackground runs fn on a goroutine that Close waits for. It returns
+// false without running fn once the client is closed.
+func (c *Client) goBackground(fn func()) bool {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+	if c.closed {
+		return false
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		fn()
+	}()
+	return true
+}
+
+// WithHeader adds a header sent on every request, e.g. Authorization or
+// X-API-Key.
+func WithHeader(name, value string) Option {
+	return func(c *Client) {
+		c.header.Add(name, value)
+	}
+}
+
+const defaultDecodeBufferSize = 32 << 10
+
+// WithDecodeBufferSize sets the size of the buffered reader placed between the
+// response body and the JSON decoder. Larger buffers mean fewer reads on bulk
+// payloads; n <= 0 decodes straight from the body.
+func WithDecodeBufferSize(n int) Option {
+	return func(c *Client) {
+		c.decodeBufferSize = n
+	}
+}
+
+// WithStrictDecoding makes responses with fields the client doesn't know
+// fail to decode, so schema drift between client and server is reported
+// instead of silently dropping data. It is off by default, which lets
+// servers add fields without breaking older clients.
+func WithStrictDecoding(strict bool) Option {
+	return func(c *Client) {
+		c.strictDecoding = strict
+	}
+}
+
+// Do sends a request with any HTTP method to path, relative to BaseURL.
+// A non-nil in is encoded as the JSON body and a non-nil out is decoded
+// from a 2xx response; other statuses are returned as an *APIError.
+// The typed methods below are thin wrappers around the same code path.
+func (c *Client) Do(ctx context.Context, method, path string, in, out any, opts ...CallOption) error {
+	return c.do(ctx, call{op: "Do", method: method, path: path, in: in, out: out, opts: opts})
+}
+
+// CallOption adjusts a single method call.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	idempotencyKey  string
+	continueOnError bool
+	noRetry         bool
+	ifMatch         string
+	timeout         time.Duration
+	meta            *ResponseMeta
+	requestID       string
+	dryRun          bool
+	softDelete      bool
+}
+
+func resolveCallOptions(opts []CallOption) callOptions {
+	var o callOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+type call struct {
+	op     string
+	method string
+	path   string
+	query  url.Values
+	in     any
+	out    any
+	opts   []CallOption
+
+	// read marks a non-GET call that doesn't mutate state, e.g. Query.
+	read bool
+	// keyed writes get an Idempotency-Key, generated unless the caller
+	// supplied one, which makes them safe to retry.
+	keyed bool
+	// warnings are passed through to RequestInfo.
+	warnings []string
+	// contentType overrides application/json for the request body.
+	contentType string
+	// respHeader, if set, receives the headers of a 2xx response.
+	respHeader *http.Header
+	// gzipped is set by do when the body has been compressed.
+	gzipped bool
+	// base overrides BaseURL, for failover between endpoints.
+	base string
+	// requestID is set by do to the X-Request-ID sent, then to the one the
+	// server echoed, if any.
+	requestID string
+	// dryRun is set by do for a WithDryRun write.
+	dryRun bool
+	// accept overrides the codec's content type in the Accept header.
+	accept string
+	// stream, if set, reads the body of a 2xx response instead of out. The
+	// client's default and adaptive timeouts don't apply; ctx bounds it.
+	stream func(io.Reader) error
+}
+
+func (c *Client) do(ctx context.Context, cl call) (err error) {
+	start := time.Now()
+	var req *http.Request
+	status := 0
+	defer func() {
+		err = c.redactError(c.timeoutError(cl, req, start, err))
+		c.observe(cl, req, status, time.Since(start), err)
+	}()
+
+	o := resolveCallOptions(cl.opts)
+	if o.dryRun && cl.mutates() {
+		if err := c.checkDryRun(ctx); err != nil {
+			return err
+		}
+		cl.dryRun = true
+	}
+	timeout := o.timeout
+	adaptive := false // whether the adaptive deadline is the one that binds
+	if timeout <= 0 && c.adaptive != nil && cl.stream == nil {
+		timeout = c.adaptive.timeout(cl.op)
+		dl, ok := ctx.Deadline()
+		adaptive = !ok || dl.After(time.Now().Add(timeout))
+	}
+	if timeout <= 0 && c.customClient == nil && cl.stream == nil {
+		// A client from WithHTTPClient brings its own Timeout.
+		timeout = c.timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var body []byte
+	if cl.in != nil {
+		body, err = c.marshal(cl)
+		if err != nil {
+			return newMarshalError(cl.op, cl.in, err)
+		}
+		body, cl.gzipped = c.compress(cl.method, body)
+	}
+
+	cl.requestID = o.requestID
+	if cl.requestID == "" {
+		cl.requestID = c.requestID()
+	}
+
+	key := o.idempotencyKey
+	if key == "" && cl.keyed {
+		key = c.idempotencyKey()
+	}
+
+	safe := key != "" || cl.read || isIdempotent(cl.method)
+	attempts := 1
+	if !o.noRetry && safe {
+		attempts = max(c.retry.MaxAttempts, 1)
+	}
+
+	for attempt := 1; ; attempt++ {
+		var resp *http.Response
+		sent := time.Now()
+		req, resp, err = c.send(ctx, cl, body, key, safe)
+		if c.adaptive != nil && req != nil {
+			c.adaptive.observe(cl.op, time.Since(sent), err, adaptive && ctx.Err() != nil)
+		}
+		if req == nil {
+			return err
+		}
+		if err == nil {
+			status = resp.StatusCode
+			if id := resp.Header.Get("X-Request-ID"); id != "" {
+				cl.requestID = id
+			}
+		}
+		if attempt < attempts && shouldRetry(resp, err) && ctx.Err() == nil {
+			wait := c.retry.backoff(attempt, resp)
+			if resp != nil {
+				_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+				resp.Body.Close()
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("http %s: %w", strings.ToLower(cl.method), err)
+		}
+		return c.handleResponse(resp, cl)
+	}
 }
 
-func NewClient(baseURL string) *Client {
-	return &Client{
-		BaseURL: baseURL,
-		client:  &http.Client{Timeout: 10 * time.Second},
+func (c *Client) newRequest(ctx context.Context, cl call, body []byte) (*http.Request, error) {
+	base := cl.base
+	if base == "" {
+		base = c.BaseURL
+	}
+	target, err := url.JoinPath(base, cl.path)
+	if err != nil {
+		return nil, fmt.Errorf("build url: %w", err)
+	}
+	if len(cl.query) > 0 {
+		target += "?" + cl.query.Encode()
+	}
+
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, cl.method, target, r)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	for name, values := range c.header {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	accept := c.codec.ContentType()
+	if cl.accept != "" {
+		accept = cl.accept
 	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Accept-Encoding", "gzip")
+	if cl.requestID != "" {
+		req.Header.Set("X-Request-ID", cl.requestID)
+	}
+	if cl.dryRun {
+		req.Header.Set("X-Dry-Run", "true")
+	}
+	if m := resolveCallOptions(cl.opts).ifMatch; m != "" {
+		req.Header.Set("If-Match", m)
+	}
+	if body != nil {
+		ct := cl.contentType
+		if ct == "" {
+			ct = c.codec.ContentType()
+		}
+		req.Header.Set("Content-Type", ct)
+		if cl.gzipped {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+	}
+	return req, nil
+}
+
+func (c *Client) handleResponse(resp *http.Response, cl call) error {
+	defer resp.Body.Close()
+	if err := gunzipBody(resp); err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return c.newAPIError(resp)
+	}
+
+	if cl.respHeader != nil {
+		*cl.respHeader = resp.Header
+	}
+	if meta := resolveCallOptions(cl.opts).meta; meta != nil {
+		*meta = newResponseMeta(resp)
+	}
+	if cl.stream != nil {
+		return cl.stream(resp.Body)
+	}
+	if cl.out == nil {
+		return nil
+	}
+	var r io.Reader = resp.Body
+	if c.maxResponseBytes > 0 {
+		r = &limitReader{r: r, n: c.maxResponseBytes}
+	}
+	if c.decodeBufferSize > 0 {
+		r = bufio.NewReaderSize(r, c.decodeBufferSize)
+	}
+	if c.codecResponse(resp) {
+		data, err := io.ReadAll(r)
+		if err == nil {
+			err = c.codec.Unmarshal(data, cl.out)
+		}
+		if err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+		return nil
+	}
+	dec := json.NewDecoder(r)
+	if c.strictDecoding {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(cl.out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
 }
 
 type QueryRequest struct {
-	Query     string   `json:"query"`
-	MaxTokens int      `json:"max_tokens,omitempty"`
-	Domains   []string `json:"domains,omitempty"`
+	Query      string     `json:"query"`
+	MaxTokens  int        `json:"max_tokens,omitempty"`
+	MaxItems   int        `json:"max_items,omitempty"`
+	MinScore   float64    `json:"min_score,omitempty"`
+	Domains    []string   `json:"domains,omitempty"`
+	Visibility Visibility `json:"visibility,omitempty"`
+	// IncludeFacets asks for counts by tag and domain in
+	// QueryResponse.Facets.
+	IncludeFacets bool `json:"include_facets,omitempty"`
+	// Cursor resumes after the last decision of a previous page; pass
+	// QueryResponse.NextCursor. It cannot be combined with SortBy.
+	Cursor string `json:"cursor,omitempty"`
+	// Embedding is a precomputed vector for Query, so the server needn't
+	// embed the text itself. Query may be sent alongside it or left empty.
+	// See WithEmbeddingDimension.
+	Embedding []float32 `json:"embedding,omitempty"`
+
+	// RecencyBoost asks the server to favour newer items, from 0 (rank by
+	// relevance alone, the default) to 1. For servers that ignore it, see
+	// QueryResponse.RerankByRecency.
+	RecencyBoost float64 `json:"recency_boost,omitempty"`
+	// Weights scales the server's scores per kind, e.g. to rank decisions
+	// above changes. Kinds left out keep a weight of 1. For servers that
+	// ignore it, see QueryResponse.Ranked.
+	Weights map[Kind]float64 `json:"weights,omitempty"`
+
+	// SortBy and Order are sent as query parameters. Order defaults to
+	// OrderDesc when SortBy is set.
+	SortBy SortBy `json:"-"`
+	Order  Order  `json:"-"`
+
+	// CreatedAfter and CreatedBefore bound the creation time of returned
+	// items and are sent as RFC3339 query parameters. Either may be zero.
+	CreatedAfter  time.Time `json:"-"`
+	CreatedBefore time.Time `json:"-"`
 }
 
 type QueryResponse struct {
@@ -31,57 +518,144 @@ type QueryResponse struct {
 	KnownIssues   []Issue    `json:"known_issues"`
 	RecentChanges []Change   `json:"recent_changes"`
 	TotalItems    int        `json:"total_items"`
+	// NextCursor is set when more decisions may follow this page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Facets maps a facet name such as FacetTag to value counts across
+	// all matches. Empty unless IncludeFacets was set and the server
+	// supports it.
+	Facets map[string][]FacetCount `json:"facets,omitempty"`
+	// QueryID identifies this query to the server, for Feedback.QueryID.
+	// Empty if the server doesn't assign one.
+	QueryID string `json:"query_id,omitempty"`
 }
 
 type Decision struct {
-	ID       string   `json:"id"`
-	Title    string   `json:"title"`
-	Decision string   `json:"decision"`
-	Tags     []string `json:"tags"`
-	Score    float64  `json:"score"`
+	ID                string              `json:"id"`
+	Title             string              `json:"title"`
+	Domain            string              `json:"domain,omitempty"`
+	Status            string              `json:"status,omitempty"`
+	Context           string              `json:"context,omitempty"`
+	Decision          string              `json:"decision"`
+	OptionsConsidered map[string][]string `json:"options_considered,omitempty"`
+	Tags              []string            `json:"tags"`
+	Stakeholders      []string            `json:"stakeholders,omitempty"`
+	Owners            []string            `json:"owners,omitempty"`
+	Evidence          []EvidenceRef       `json:"evidence,omitempty"`
+	Visibility        Visibility          `json:"visibility,omitempty"`
+	Score             float64             `json:"score"`
+	CreatedAt         time.Time           `json:"created_at"`
+	ReviewedAt        time.Time           `json:"reviewed_at"`
+	// OptionsConsideredOrdered is set by servers that keep the order the
+	// options were recorded in.
+	OptionsConsideredOrdered []ConsideredOption `json:"options_considered_ordered,omitempty"`
+	// Supersedes and SupersededBy link the decision into a history; see
+	// SupersedeADR and ADRHistory.
+	Supersedes   string `json:"supersedes,omitempty"`
+	SupersededBy string `json:"superseded_by,omitempty"`
+	// DeletedAt is set on soft-deleted decisions, which ListADRs returns
+	// only with ADRFilter.IncludeDeleted.
+	DeletedAt time.Time `json:"deleted_at,omitempty"`
+	// ETag is the version GetADR read, for WithIfMatch.
+	ETag string `json:"-"`
 }
 
 type Issue struct {
-	ID         string   `json:"id"`
-	Title      string   `json:"title"`
-	RootCause  string   `json:"root_cause"`
-	Resolution string   `json:"resolution"`
-	Pattern    string   `json:"pattern"`
-	Tags       []string `json:"tags"`
+	ID         string          `json:"id"`
+	Title      string          `json:"title"`
+	RootCause  string          `json:"root_cause"`
+	Resolution string          `json:"resolution"`
+	Runbook    string          `json:"runbook,omitempty"`
+	Pattern    Pattern         `json:"pattern"`
+	Tags       []string        `json:"tags"`
+	Timeline   []TimelineEvent `json:"timeline,omitempty"`
+	// RelatedADRs are the IDs of decisions that caused or govern the
+	// failure.
+	RelatedADRs []string  `json:"related_adrs,omitempty"`
+	Score       float64   `json:"score"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Symptoms through Severity echo the FailureRequest the issue was
+	// recorded from.
+	Symptoms   string   `json:"symptoms,omitempty"`
+	Impact     string   `json:"impact,omitempty"`
+	Prevention []string `json:"prevention,omitempty"`
+	Severity   Severity `json:"severity,omitempty"`
 }
 
 type Change struct {
-	ID    string   `json:"id"`
-	Type  string   `json:"type"`
-	Title string   `json:"title"`
-	Tags  []string `json:"tags"`
+	ID          string     `json:"id"`
+	Type        ChangeType `json:"type"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Tags        []string   `json:"tags"`
+	Score       float64    `json:"score"`
+	CreatedAt   time.Time  `json:"created_at"`
 }
 
-func (c *Client) Query(req QueryRequest) (*QueryResponse, error) {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
+func (c *Client) Query(ctx context.Context, req QueryRequest, opts ...CallOption) (*QueryResponse, error) {
+	if c.cache != nil {
+		if resp, ok := c.cachedQuery(req, opts); ok {
+			return resp, nil
+		}
 	}
+	return c.fetchQuery(ctx, req, opts)
+}
 
-	resp, err := c.client.Post(
-		c.BaseURL+"/context/query",
-		"application/json",
-		bytes.NewReader(body),
-	)
+// fetchQuery sends req to the server, bypassing the query cache but
+// refreshing it.
+func (c *Client) fetchQuery(ctx context.Context, req QueryRequest, opts []CallOption) (*QueryResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	var warnings []string
+	if err := c.ValidateDomains(req.Domains); err != nil {
+		if c.validateDomains {
+			return nil, err
+		}
+		warnings = append(warnings, err.Error())
+	}
+	body, err := c.embed(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("http post: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	var result QueryResponse
+	err = c.do(ctx, call{
+		op:       "Query",
+		method:   http.MethodPost,
+		path:     "/context/query",
+		query:    req.values(),
+		in:       body,
+		out:      &result,
+		opts:     opts,
+		read:     true,
+		warnings: warnings,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var result QueryResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	// Not every server honors min_score, so enforce it here too.
+	if req.MinScore > 0 {
+		kept := result.KeyDecisions[:0]
+		for _, d := range result.KeyDecisions {
+			if d.Score >= req.MinScore {
+				kept = append(kept, d)
+			}
+		}
+		result.KeyDecisions = kept
+	}
+	if c.dedup {
+		result.Dedup()
+	}
+	SortDecisions(result.KeyDecisions, req.SortBy, req.orderOrDefault())
+	result.page(req)
+	if c.tokenBudget && req.MaxTokens > 0 {
+		result.truncateTokens(req.MaxTokens)
 	}
 
+	if c.cache != nil {
+		c.cache.put(cacheKey(req), &result)
+	}
 	return &result, nil
 }
 
@@ -92,29 +666,44 @@ type ADRRequest struct {
 	OptionsConsidered map[string][]string `json:"options_considered,omitempty"`
 	Tags              []string            `json:"tags,omitempty"`
 	Stakeholders      []string            `json:"stakeholders,omitempty"`
+	// Owners are the CODEOWNERS entries (users or teams) whose code the
+	// decision governs; Stakeholders are everyone with an interest in it.
+	Owners     []string      `json:"owners,omitempty"`
+	Evidence   []EvidenceRef `json:"evidence,omitempty"`
+	Visibility Visibility    `json:"visibility,omitempty"`
+	// OptionsConsideredOrdered is OptionsConsidered with the order kept,
+	// for reproducible records; set one or the other. It is sent in both
+	// forms so servers that only know the map still get the options.
+	OptionsConsideredOrdered []ConsideredOption `json:"options_considered_ordered,omitempty"`
+	// DedupBy asks the server to reject or merge duplicates of this ADR;
+	// empty leaves it to the server's default.
+	DedupBy ADRDedupStrategy `json:"dedup_by,omitempty"`
 }
 
-func (c *Client) CreateADR(req ADRRequest) error {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return fmt.Errorf("marshal request: %w", err)
-	}
+// EvidenceRef cites what a decision was based on, such as a benchmark, an
+// incident ID or a design doc. Type is free-form, e.g. "benchmark".
+type EvidenceRef struct {
+	Type        string `json:"type,omitempty"`
+	Ref         string `json:"ref"`
+	Description string `json:"description,omitempty"`
+}
 
-	resp, err := c.client.Post(
-		c.BaseURL+"/adr",
-		"application/json",
-		bytes.NewReader(body),
-	)
-	if err != nil {
-		return fmt.Errorf("http post: %w", err)
+func (c *Client) CreateADR(ctx context.Context, req ADRRequest, opts ...CallOption) error {
+	if err := req.Validate(); err != nil {
+		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	if req.Visibility == "" {
+		req.Visibility = c.visibility
 	}
-
-	return nil
+	req.Tags = c.tags(req.Tags)
+	return c.write(ctx, call{
+		op:     "CreateADR",
+		method: http.MethodPost,
+		path:   "/adr",
+		in:     req.payload(),
+		opts:   opts,
+		keyed:  true,
+	})
 }
 
 type FailureRequest struct {
@@ -124,30 +713,68 @@ type FailureRequest struct {
 	Impact     string   `json:"impact"`
 	Resolution string   `json:"resolution"`
 	Prevention []string `json:"prevention,omitempty"`
-	Severity   string   `json:"severity"`
-	Pattern    string   `json:"pattern,omitempty"`
-	Tags       []string `json:"tags,omitempty"`
+	// Runbook is a URL or inline Markdown describing how to remediate the
+	// failure. It is returned on matching Issues so on-call can follow it.
+	Runbook  string          `json:"runbook,omitempty"`
+	Severity Severity        `json:"severity"`
+	Pattern  Pattern         `json:"pattern,omitempty"`
+	Tags     []string        `json:"tags,omitempty"`
+	Timeline []TimelineEvent `json:"timeline,omitempty"`
 }
 
-func (c *Client) RecordFailure(req FailureRequest) error {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return fmt.Errorf("marshal request: %w", err)
+func (c *Client) RecordFailure(ctx context.Context, req FailureRequest, opts ...CallOption) error {
+	if err := req.Validate(); err != nil {
+		return err
 	}
-
-	resp, err := c.client.Post(
-		c.BaseURL+"/failure",
-		"application/json",
-		bytes.NewReader(body),
-	)
-	if err != nil {
-		return fmt.Errorf("http post: %w", err)
+	req.Tags = c.tags(req.Tags)
+	if c.failures != nil && !resolveCallOptions(opts).dryRun {
+		// Under closeMu so the record is either in the buffer before Close
+		// flushes it or refused.
+		c.closeMu.Lock()
+		defer c.closeMu.Unlock()
+		if c.closed {
+			return ErrClientClosed
+		}
+		c.failures.add(req)
+		return nil
 	}
-	defer resp.Body.Close()
+	return c.write(ctx, call{
+		op:     "RecordFailure",
+		method: http.MethodPost,
+		path:   "/failure",
+		in:     req,
+		opts:   opts,
+		keyed:  true,
+	})
+}
 
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
-	}
+// FailurePatch is a partial update for a recorded failure. Nil fields are
+// left untouched by the server.
+type FailurePatch struct {
+	RootCause  *string   `json:"root_cause,omitempty"`
+	Impact     *string   `json:"impact,omitempty"`
+	Resolution *string   `json:"resolution,omitempty"`
+	Runbook    *string   `json:"runbook,omitempty"`
+	Severity   *Severity `json:"severity,omitempty"`
+	Prevention []string  `json:"prevention,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+}
 
-	return nil
+func (c *Client) PatchFailure(ctx context.Context, id string, patch FailurePatch, opts ...CallOption) error {
+	return c.do(ctx, call{
+		op:     "PatchFailure",
+		method: http.MethodPatch,
+		path:   "/failure/" + url.PathEscape(id),
+		in:     patch,
+		opts:   opts,
+	})
+}
+
+func (c *Client) UpdateFailureResolution(ctx context.Context, id, resolution string, opts ...CallOption) error {
+	return c.PatchFailure(ctx, id, FailurePatch{Resolution: &resolution}, opts...)
+}
+
+// UpdateRunbook replaces the runbook linked to failure id.
+func (c *Client) UpdateRunbook(ctx context.Context, id, runbook string, opts ...CallOption) error {
+	return c.PatchFailure(ctx, id, FailurePatch{Runbook: &runbook}, opts...)
 }
@@ -0,0 +1,150 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDoPatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("method = %s, want PATCH", r.Method)
+		}
+		if r.URL.Path != "/failure/f-1" {
+			t.Errorf("path = %s, want /failure/f-1", r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if body["status"] != "resolved" {
+			t.Errorf("body = %v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"f-1","status":"updated"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	var out struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	err := c.Do(context.Background(), http.MethodPatch, "/failure/f-1", map[string]string{"status": "resolved"}, &out)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if out.ID != "f-1" || out.Status != "updated" {
+		t.Errorf("out = %+v", out)
+	}
+}
+
+func TestUpdateFailureResolution(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/failure/f-2" {
+			t.Errorf("got %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	if err := c.UpdateFailureResolution(context.Background(), "f-2", "Raised pool size"); err != nil {
+		t.Fatalf("UpdateFailureResolution: %v", err)
+	}
+	if len(got) != 1 || got["resolution"] != "Raised pool size" {
+		t.Errorf("body = %v, want only resolution", got)
+	}
+}
+
+func BenchmarkQueryDecodeBufferSize(b *testing.B) {
+	var resp QueryResponse
+	for i := 0; i < 10000; i++ {
+		resp.KeyDecisions = append(resp.KeyDecisions, Decision{
+			ID:       fmt.Sprintf("ADR-%05d", i),
+			Title:    "Use connection pooling for the primary database",
+			Decision: strings.Repeat("Pool connections with a bounded max. ", 8),
+			Tags:     []string{"database", "performance"},
+			Score:    0.5,
+		})
+	}
+	payload, err := json.Marshal(resp)
+	if err != nil {
+		b.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer srv.Close()
+
+	for _, size := range []int{0, 4 << 10, defaultDecodeBufferSize, 256 << 10} {
+		b.Run(fmt.Sprintf("buf=%d", size), func(b *testing.B) {
+			c := NewClient(srv.URL, WithDecodeBufferSize(size))
+			b.SetBytes(int64(len(payload)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.Query(context.Background(), QueryRequest{Query: "bench"}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestBaseURLJoin(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Path
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		suffix string
+		want   string
+	}{
+		{"", "/context/query"},
+		{"/", "/context/query"},
+		{"/api", "/api/context/query"},
+		{"/api/", "/api/context/query"},
+		{"/api//", "/api/context/query"},
+		{"/v1/api", "/v1/api/context/query"},
+		{"/v1/api/", "/v1/api/context/query"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.suffix, func(t *testing.T) {
+			c := NewClient(srv.URL + tt.suffix)
+			if _, err := c.Query(context.Background(), QueryRequest{Query: "q"}); err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("path = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrictDecodingRejectsUnknownFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"decisions":[{"id":"adr-1"}]}`)
+	}))
+	defer srv.Close()
+
+	if _, err := NewClient(srv.URL).Query(context.Background(), QueryRequest{Query: "q"}); err != nil {
+		t.Errorf("lenient err = %v", err)
+	}
+	_, err := NewClient(srv.URL, WithStrictDecoding(true)).Query(context.Background(), QueryRequest{Query: "q"})
+	if err == nil || !strings.Contains(err.Error(), `unknown field "decisions"`) {
+		t.Errorf("strict err = %v", err)
+	}
+}
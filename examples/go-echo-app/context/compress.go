@@ -0,0 +1,71 @@
+package context
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// WithRequestCompression gzips request bodies of at least minBytes on POST,
+// PUT and PATCH and sends them with Content-Encoding: gzip. It is off by
+// default because not every server accepts compressed requests; minBytes
+// <= 0 disables it.
+func WithRequestCompression(minBytes int) Option {
+	return func(c *Client) {
+		c.compressMin = minBytes
+	}
+}
+
+// compress returns body gzipped if the client is configured to compress it,
+// and whether it did.
+func (c *Client) compress(method string, body []byte) ([]byte, bool) {
+	if c.compressMin <= 0 || len(body) < c.compressMin {
+		return body, false
+	}
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return body, false
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return body, false
+	}
+	if err := zw.Close(); err != nil {
+		return body, false
+	}
+	return buf.Bytes(), true
+}
+
+// gunzipBody replaces resp.Body with a decompressing reader when the server
+// sent Content-Encoding: gzip. Go's transport only does this itself when the
+// caller hasn't set Accept-Encoding, and newRequest sets it.
+func gunzipBody(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	resp.Body = gzipBody{zr, resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	return nil
+}
+
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
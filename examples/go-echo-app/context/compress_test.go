@@ -0,0 +1,170 @@
+package context
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestCompression(t *testing.T) {
+	type received struct {
+		method, encoding, body string
+	}
+	var (
+		mu   sync.Mutex
+		got  []received
+		fail atomic.Int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("%s: %v", r.Method, err)
+				return
+			}
+			body = zr
+		}
+		b, err := io.ReadAll(body)
+		if err != nil {
+			t.Errorf("%s: %v", r.Method, err)
+		}
+		mu.Lock()
+		got = append(got, received{r.Method, r.Header.Get("Content-Encoding"), string(b)})
+		mu.Unlock()
+		if fail.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+	last := func() received {
+		mu.Lock()
+		defer mu.Unlock()
+		return got[len(got)-1]
+	}
+
+	large := map[string]string{"text": strings.Repeat("x", 100)}
+	small := map[string]string{"t": "x"}
+	c := NewClient(srv.URL, WithRequestCompression(64),
+		WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+	for _, tc := range []struct {
+		method string
+		in     any
+		want   string
+	}{
+		{http.MethodPost, large, "gzip"},
+		{http.MethodPut, large, "gzip"},
+		{http.MethodPatch, large, "gzip"},
+		{http.MethodPost, small, ""},
+		{http.MethodGet, large, ""},
+	} {
+		if err := c.Do(context.Background(), tc.method, "/x", tc.in, nil); err != nil {
+			t.Fatalf("%s: %v", tc.method, err)
+		}
+		r := last()
+		want, _ := json.Marshal(tc.in)
+		if r.encoding != tc.want || r.body != string(want) {
+			t.Errorf("%s %d bytes: encoding = %q, body = %q", tc.method, len(want), r.encoding, r.body)
+		}
+	}
+
+	// A retry resends the same compressed body.
+	mu.Lock()
+	got = nil
+	mu.Unlock()
+	fail.Store(1)
+	q := QueryRequest{Query: strings.Repeat("q", 100)}
+	if _, err := c.Query(context.Background(), q); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := json.Marshal(q)
+	mu.Lock()
+	if len(got) != 2 {
+		t.Fatalf("retry: %d requests, want 2", len(got))
+	}
+	for i, r := range got {
+		if r.encoding != "gzip" || r.body != string(want) {
+			t.Errorf("attempt %d: encoding = %q, body = %q", i+1, r.encoding, r.body)
+		}
+	}
+	mu.Unlock()
+}
+
+// A hedge resends the same compressed body while the first attempt stalls.
+func TestRequestCompressionHedge(t *testing.T) {
+	q := QueryRequest{Query: strings.Repeat("q", 100)}
+	want, _ := json.Marshal(q)
+	var calls atomic.Int32
+	var hedged atomic.Value
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("hedge: %v", err)
+			return
+		}
+		b, _ := io.ReadAll(zr)
+		hedged.Store(string(b))
+		w.Write([]byte("{}"))
+	}))
+	defer slow.Close()
+	defer close(release)
+	h := NewClient(slow.URL, WithRequestCompression(64), WithHedging(10*time.Millisecond))
+	if _, err := h.Query(context.Background(), q); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := hedged.Load().(string); b != string(want) {
+		t.Errorf("hedge body = %q", b)
+	}
+}
+
+func TestGzipRoundTrip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("request Content-Encoding = %q", r.Header.Get("Content-Encoding"))
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var req QueryRequest
+		if err := json.NewDecoder(zr).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Accept-Encoding = %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_ = json.NewEncoder(zw).Encode(QueryResponse{KeyDecisions: []Decision{{ID: req.Query}}})
+		zw.Close()
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithRequestCompression(1))
+	resp, err := c.Query(context.Background(), QueryRequest{Query: "adr-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.KeyDecisions) != 1 || resp.KeyDecisions[0].ID != "adr-1" {
+		t.Errorf("decisions = %+v", resp.KeyDecisions)
+	}
+}
@@ -0,0 +1,36 @@
+package context
+
+import "sort"
+
+// ConsideredOption is one alternative weighed by a decision, for
+// ADRRequest.OptionsConsideredOrdered.
+type ConsideredOption struct {
+	Name string   `json:"name"`
+	Pros []string `json:"pros,omitempty"`
+}
+
+// orderedOptions returns ordered if set, and otherwise m's options sorted by
+// name.
+func orderedOptions(m map[string][]string, ordered []ConsideredOption) []ConsideredOption {
+	if len(ordered) > 0 {
+		return ordered
+	}
+	out := make([]ConsideredOption, 0, len(m))
+	for name, pros := range m {
+		out = append(out, ConsideredOption{Name: name, Pros: pros})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// optionsMap is the map form of ordered, or nil if it is empty.
+func optionsMap(ordered []ConsideredOption) map[string][]string {
+	if len(ordered) == 0 {
+		return nil
+	}
+	m := make(map[string][]string, len(ordered))
+	for _, o := range ordered {
+		m[o.Name] = o.Pros
+	}
+	return m
+}
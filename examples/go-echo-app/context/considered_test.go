@@ -0,0 +1,49 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOptionsConsideredOrdered(t *testing.T) {
+	var got ADRRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	req := ADRRequest{Title: "Router", Decision: "Use Echo", OptionsConsideredOrdered: []ConsideredOption{
+		{Name: "zeta", Pros: []string{"small"}},
+		{Name: "alpha", Pros: []string{"fast", "known"}},
+	}}
+	if err := NewClient(srv.URL).CreateADR(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.OptionsConsideredOrdered) != 2 || got.OptionsConsideredOrdered[0].Name != "zeta" {
+		t.Errorf("ordered = %+v", got.OptionsConsideredOrdered)
+	}
+	if len(got.OptionsConsidered["alpha"]) != 2 || len(got.OptionsConsidered["zeta"]) != 1 {
+		t.Errorf("map = %v", got.OptionsConsidered)
+	}
+
+	md := req.Markdown()
+	if z, a := strings.Index(md, "**zeta**"), strings.Index(md, "**alpha**"); z < 0 || a < z {
+		t.Errorf("options out of order:\n%s", md)
+	}
+
+	both := req
+	both.OptionsConsidered = map[string][]string{"gin": {"popular"}}
+	dup := req
+	dup.OptionsConsideredOrdered = append(dup.OptionsConsideredOrdered, ConsideredOption{Name: "zeta"})
+	var verr *ValidationError
+	for name, r := range map[string]ADRRequest{"both": both, "duplicate": dup} {
+		if err := r.Validate(); !errors.As(err, &verr) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+}
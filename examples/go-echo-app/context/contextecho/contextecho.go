@@ -0,0 +1,167 @@
+// Package contextecho provides Echo middleware for the context client.
+package contextecho
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/example/go-echo-app/context"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultDedupWindow   = time.Minute
+	defaultTimeout       = 5 * time.Second
+	defaultInjectTimeout = time.Second
+)
+
+type config struct {
+	window   time.Duration
+	severity context.Severity
+	tags     []string
+	timeout  time.Duration
+	onError  func(error)
+}
+
+// Option configures RecordFailures and Inject.
+type Option func(*config)
+
+// WithDedupWindow sets how long an identical failure (same method, route,
+// status and error) is suppressed after being recorded. The default is one
+// minute.
+func WithDedupWindow(d time.Duration) Option {
+	return func(c *config) {
+		c.window = d
+	}
+}
+
+// WithSeverity sets the severity of recorded failures. The default is
+// SeverityHigh.
+func WithSeverity(s context.Severity) Option {
+	return func(c *config) {
+		c.severity = s
+	}
+}
+
+// WithTags adds tags to every recorded failure.
+func WithTags(tags ...string) Option {
+	return func(c *config) {
+		c.tags = append(c.tags, tags...)
+	}
+}
+
+// WithTimeout bounds each call the middleware makes: 5s by default for
+// RecordFailures and 1s for Inject, which holds up the request.
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.timeout = d
+	}
+}
+
+// WithErrorHandler receives errors from recording a failure or running an
+// Inject query. The default logs them.
+func WithErrorHandler(fn func(error)) Option {
+	return func(c *config) {
+		c.onError = fn
+	}
+}
+
+// RecordFailures records every 5xx response as a failure, with the route,
+// method, status and handler error. Recording happens in the background and
+// never changes the response. Bursts of the same failure are collapsed into
+// one record per dedup window.
+func RecordFailures(client *context.Client, opts ...Option) echo.MiddlewareFunc {
+	cfg := newConfig(defaultTimeout, opts)
+	d := &dedup{window: cfg.window, seen: make(map[string]time.Time)}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+
+			status := c.Response().Status
+			if err != nil {
+				// The error handler runs after middleware, so work out
+				// the status it will send.
+				status = http.StatusInternalServerError
+				var he *echo.HTTPError
+				if errors.As(err, &he) {
+					status = he.Code
+				}
+			}
+			if status < 500 {
+				return err
+			}
+
+			method, route := c.Request().Method, c.Path()
+			rootCause := fmt.Sprintf("handler returned status %d", status)
+			if err != nil {
+				rootCause = err.Error()
+			}
+			if !d.first(method+" "+route+" "+fmt.Sprint(status)+" "+rootCause, time.Now()) {
+				return err
+			}
+
+			req := context.FailureRequest{
+				Title:     fmt.Sprintf("%s %s returned %d", method, route, status),
+				RootCause: rootCause,
+				Symptoms:  fmt.Sprintf("%s %s responded %d %s", method, c.Request().URL.Path, status, http.StatusText(status)),
+				Severity:  cfg.severity,
+				Tags:      append([]string{"http", "5xx"}, cfg.tags...),
+			}
+			go func() {
+				ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), cfg.timeout)
+				defer cancel()
+				if rerr := client.RecordFailure(ctx, req); rerr != nil {
+					cfg.onError(fmt.Errorf("record failure %q: %w", req.Title, rerr))
+				}
+			}()
+			return err
+		}
+	}
+}
+
+func newConfig(timeout time.Duration, opts []Option) config {
+	cfg := config{
+		window:   defaultDedupWindow,
+		severity: context.SeverityHigh,
+		timeout:  timeout,
+		onError: func(err error) {
+			log.Printf("contextecho: %v", err)
+		},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// dedup reports whether a key was not seen within the window.
+type dedup struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func (d *dedup) first(key string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if last, ok := d.seen[key]; ok && now.Sub(last) < d.window {
+		return false
+	}
+	d.seen[key] = now
+	// Drop expired keys so the map doesn't grow with every distinct error.
+	if len(d.seen) > 1024 {
+		for k, t := range d.seen {
+			if now.Sub(t) >= d.window {
+				delete(d.seen, k)
+			}
+		}
+	}
+	return true
+}
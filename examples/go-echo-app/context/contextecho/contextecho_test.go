@@ -0,0 +1,75 @@
+package contextecho
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/example/go-echo-app/context"
+	"github.com/labstack/echo/v4"
+)
+
+func TestRecordFailuresDedupsBursts(t *testing.T) {
+	failures := make(chan context.FailureRequest, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req context.FailureRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		failures <- req
+	}))
+	defer srv.Close()
+
+	e := echo.New()
+	e.Use(RecordFailures(context.NewClient(srv.URL)))
+	e.GET("/users/:id", func(c echo.Context) error { return errors.New("db down") })
+	e.GET("/ok", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	for i := 0; i < 3; i++ {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	}
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	select {
+	case f := <-failures:
+		if f.Title != "GET /users/:id returned 500" || f.RootCause != "db down" {
+			t.Errorf("failure = %+v", f)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no failure recorded")
+	}
+	select {
+	case f := <-failures:
+		t.Errorf("unexpected second failure %+v", f)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestInjectFailsOpen(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"key_decisions":[{"id":"adr-1"}]}`))
+	}))
+	defer srv.Close()
+
+	var got []bool
+	e := echo.New()
+	e.Use(Inject(context.NewClient(srv.URL), func(c echo.Context) context.QueryRequest {
+		return context.QueryRequest{Query: "q"}
+	}, WithErrorHandler(func(error) {})))
+	e.GET("/", func(c echo.Context) error {
+		resp, ok := FromContext(c)
+		got = append(got, ok && resp.KeyDecisions[0].ID == "adr-1")
+		return nil
+	})
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(got) != 2 || !got[0] || got[1] {
+		t.Errorf("handler saw context = %v, want [true false]", got)
+	}
+}
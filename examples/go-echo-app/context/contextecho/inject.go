@@ -0,0 +1,41 @@
+package contextecho
+
+import (
+	"fmt"
+
+	"github.com/example/go-echo-app/context"
+	"github.com/labstack/echo/v4"
+)
+
+// ContextKey is the echo.Context key Inject stores the *QueryResponse under.
+const ContextKey = "contextecho.query"
+
+// Inject runs the query queryFor builds for each request and stores the
+// result for FromContext before calling the handler. It fails open: if the
+// query errors or exceeds its timeout the handler runs without context. A
+// request with an empty Query is passed through without querying.
+func Inject(client *context.Client, queryFor func(echo.Context) context.QueryRequest, opts ...Option) echo.MiddlewareFunc {
+	cfg := newConfig(defaultInjectTimeout, opts)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := queryFor(c)
+			if req.Query == "" {
+				return next(c)
+			}
+			resp, err := client.Query(c.Request().Context(), req, context.WithCallTimeout(cfg.timeout))
+			if err != nil {
+				cfg.onError(fmt.Errorf("context query for %s %s skipped: %w", c.Request().Method, c.Path(), err))
+				return next(c)
+			}
+			c.Set(ContextKey, resp)
+			return next(c)
+		}
+	}
+}
+
+// FromContext returns the response Inject stored for this request, if any.
+func FromContext(c echo.Context) (*context.QueryResponse, bool) {
+	resp, ok := c.Get(ContextKey).(*context.QueryResponse)
+	return resp, ok
+}
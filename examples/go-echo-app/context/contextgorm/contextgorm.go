@@ -0,0 +1,152 @@
+// Package contextgorm records GORM model mutations as context engine
+// Changes, giving an audit trail of creates, updates and deletes without
+// touching the handlers that make them.
+package contextgorm
+
+import (
+	stdcontext "context"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/example/go-echo-app/context"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTimeout     = 5 * time.Second
+	defaultConcurrency = 8
+)
+
+type config struct {
+	tags        []string
+	timeout     time.Duration
+	onError     func(error)
+	concurrency int
+}
+
+// Option configures Register.
+type Option func(*config)
+
+// WithTags adds tags to every recorded change, after "gorm" and the
+// lower-cased model name.
+func WithTags(tags ...string) Option {
+	return func(c *config) {
+		c.tags = append(c.tags, tags...)
+	}
+}
+
+// WithTimeout bounds each CreateChange call. The default is 5s.
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.timeout = d
+	}
+}
+
+// WithErrorHandler receives errors from recording a change and changes
+// dropped because too many were in flight. The default logs them.
+func WithErrorHandler(fn func(error)) Option {
+	return func(c *config) {
+		c.onError = fn
+	}
+}
+
+// Register hooks db so every successful create, update and delete is
+// recorded with client.CreateChange as a change of type "model.create",
+// "model.update" or "model.delete" titled with the model name and primary
+// key. Recording is asynchronous and best-effort: it never fails or delays
+// the database operation, and changes are dropped rather than queued when
+// the context engine falls behind.
+func Register(db *gorm.DB, client *context.Client, opts ...Option) error {
+	cfg := config{
+		timeout:     defaultTimeout,
+		concurrency: defaultConcurrency,
+		onError: func(err error) {
+			log.Printf("contextgorm: %v", err)
+		},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	r := &recorder{client: client, cfg: cfg, sem: make(chan struct{}, cfg.concurrency)}
+
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:commit_or_rollback_transaction").Register("contextgorm:after_create", r.hook("create")); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:commit_or_rollback_transaction").Register("contextgorm:after_update", r.hook("update")); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:commit_or_rollback_transaction").Register("contextgorm:after_delete", r.hook("delete"))
+}
+
+type recorder struct {
+	client *context.Client
+	cfg    config
+	sem    chan struct{}
+}
+
+func (r *recorder) hook(op string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		if tx.Error != nil || tx.RowsAffected == 0 || tx.Statement.Schema == nil {
+			return
+		}
+		model := tx.Statement.Schema.Name
+		for _, id := range primaryKeys(tx) {
+			req := context.ChangeRequest{
+				Type:  context.ChangeType("model." + op),
+				Title: strings.TrimSpace(fmt.Sprintf("%s %s %s", op, model, id)),
+				Tags:  append([]string{"gorm", strings.ToLower(model)}, r.cfg.tags...),
+			}
+			select {
+			case r.sem <- struct{}{}:
+				go r.record(req)
+			default:
+				r.cfg.onError(fmt.Errorf("dropped change %q: too many in flight", req.Title))
+			}
+		}
+	}
+}
+
+func (r *recorder) record(req context.ChangeRequest) {
+	defer func() { <-r.sem }()
+	ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), r.cfg.timeout)
+	defer cancel()
+	if _, err := r.client.CreateChange(ctx, req); err != nil {
+		r.cfg.onError(fmt.Errorf("record change %q: %w", req.Title, err))
+	}
+}
+
+// primaryKeys returns the primary key of each affected row the statement
+// holds in memory. A delete by condition alone has none, and is recorded
+// once without an ID.
+func primaryKeys(tx *gorm.DB) []string {
+	field := tx.Statement.Schema.PrioritizedPrimaryField
+	rv := reflect.Indirect(tx.Statement.ReflectValue)
+	if field == nil || !rv.IsValid() {
+		return []string{""}
+	}
+
+	var rows []reflect.Value
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			rows = append(rows, reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		rows = []reflect.Value{rv}
+	}
+
+	var ids []string
+	for _, row := range rows {
+		if v, zero := field.ValueOf(tx.Statement.Context, row); !zero {
+			ids = append(ids, fmt.Sprint(v))
+		}
+	}
+	if len(ids) == 0 {
+		return []string{""}
+	}
+	return ids
+}
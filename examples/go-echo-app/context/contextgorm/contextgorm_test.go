@@ -0,0 +1,58 @@
+package contextgorm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/example/go-echo-app/context"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func TestRegisterRecordsMutations(t *testing.T) {
+	changes := make(chan context.ChangeRequest, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req context.ChangeRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		changes <- req
+		w.Write([]byte(`{"id":"chg"}`))
+	}))
+	defer srv.Close()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&widget{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Register(db, context.NewClient(srv.URL)); err != nil {
+		t.Fatal(err)
+	}
+
+	w := widget{Name: "a"}
+	db.Create(&w)
+	db.Model(&w).Update("name", "b")
+	db.Delete(&w)
+
+	want := map[string]bool{"create widget 1": true, "update widget 1": true, "delete widget 1": true}
+	for n := len(want); n > 0; n-- {
+		select {
+		case c := <-changes:
+			if !want[c.Title] || c.Tags[0] != "gorm" {
+				t.Errorf("unexpected change %+v", c)
+			}
+			delete(want, c.Title)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("missing changes: %v", want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Package contextmsgpack provides a MessagePack codec for the context
+// client, for high-volume callers where JSON encoding is a measurable cost.
+// It lives in its own package so the core client does not depend on a
+// msgpack library. The server must accept and send application/msgpack.
+package contextmsgpack
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	"github.com/example/go-echo-app/context"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ContentType is the media type sent in Content-Type and Accept.
+const ContentType = "application/msgpack"
+
+// Codec encodes with the same field names as the JSON API: struct fields
+// are keyed by their json tags, including omitempty.
+var Codec context.Codec = codec{}
+
+type codec struct{}
+
+func init() {
+	// Severity's JSON methods lowercase it; msgpack doesn't call them.
+	msgpack.Register(context.Severity(""),
+		func(e *msgpack.Encoder, v reflect.Value) error {
+			return e.EncodeString(strings.ToLower(v.String()))
+		},
+		func(d *msgpack.Decoder, v reflect.Value) error {
+			s, err := d.DecodeString()
+			v.SetString(strings.ToLower(s))
+			return err
+		})
+}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+func (codec) ContentType() string { return ContentType }
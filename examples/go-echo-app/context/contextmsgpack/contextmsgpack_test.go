@@ -0,0 +1,80 @@
+package contextmsgpack
+
+import (
+	stdcontext "context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/example/go-echo-app/context"
+)
+
+func TestQueryRoundTrip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != ContentType {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if a := r.Header.Get("Accept"); a != ContentType {
+			t.Errorf("Accept = %q", a)
+		}
+		data, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		if err := Codec.Unmarshal(data, &req); err != nil {
+			t.Fatal(err)
+		}
+		if req["query"] != "caching" {
+			t.Errorf("request = %v", req)
+		}
+
+		out, err := Codec.Marshal(context.QueryResponse{
+			KeyDecisions: []context.Decision{{ID: "ADR-1", Title: "Use Redis", Score: 0.9}},
+			TotalItems:   1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Header().Set("Content-Type", ContentType)
+		w.Write(out)
+	}))
+	defer srv.Close()
+
+	c := context.NewClient(srv.URL, context.WithCodec(Codec))
+	resp, err := c.Query(stdcontext.Background(), context.QueryRequest{Query: "caching"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.KeyDecisions) != 1 || resp.KeyDecisions[0].Title != "Use Redis" || resp.KeyDecisions[0].Score != 0.9 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestWriteFields(t *testing.T) {
+	bodies := make(chan map[string]any, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		if err := Codec.Unmarshal(data, &body); err != nil {
+			t.Error(err)
+		}
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	c := context.NewClient(srv.URL, context.WithCodec(Codec))
+	adr := context.ADRRequest{Title: "Use Redis", Context: "c", Decision: "d"}
+	if err := c.CreateADR(stdcontext.Background(), adr); err != nil {
+		t.Fatal(err)
+	}
+	if body := <-bodies; body["title"] != "Use Redis" || body["content_hash"] != adr.ContentHash() {
+		t.Errorf("ADR body = %v", body)
+	}
+
+	fail := context.FailureRequest{Title: "t", RootCause: "r", Severity: "HIGH"}
+	if err := c.RecordFailure(stdcontext.Background(), fail); err != nil {
+		t.Fatal(err)
+	}
+	if body := <-bodies; body["severity"] != "high" {
+		t.Errorf("failure body = %v", body)
+	}
+}
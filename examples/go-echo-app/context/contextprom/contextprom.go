@@ -0,0 +1,101 @@
+// Package contextprom exports context client metrics to Prometheus. It lives
+// in its own package so the core client does not depend on client_golang.
+package contextprom
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/example/go-echo-app/context"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "context_client"
+
+// Recorder implements context.MetricsRecorder on top of Prometheus
+// collectors.
+type Recorder struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	cache    *prometheus.CounterVec
+}
+
+// WithMetrics registers the client collectors with reg and attaches them to
+// the client. Several clients may share one registry.
+func WithMetrics(reg prometheus.Registerer) context.Option {
+	return context.WithMetricsRecorder(New(reg))
+}
+
+func New(reg prometheus.Registerer) *Recorder {
+	r := &Recorder{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Context engine requests by client method and outcome.",
+		}, []string{"method", "outcome"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Failed context engine requests by client method and status class.",
+		}, []string{"method", "class"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Context engine request latency by client method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Query cache lookups by client method and result.",
+		}, []string{"method", "result"}),
+	}
+	r.requests = register(reg, r.requests)
+	r.errors = register(reg, r.errors)
+	r.latency = register(reg, r.latency)
+	r.cache = register(reg, r.cache)
+	return r
+}
+
+func (r *Recorder) ObserveRequest(op string, statusCode int, err error, d time.Duration) {
+	outcome := "success"
+	if err != nil {
+		class := statusClass(statusCode)
+		outcome = "error"
+		r.errors.WithLabelValues(op, class).Inc()
+	}
+	r.requests.WithLabelValues(op, outcome).Inc()
+	r.latency.WithLabelValues(op).Observe(d.Seconds())
+}
+
+func (r *Recorder) ObserveCache(op string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	r.cache.WithLabelValues(op, result).Inc()
+}
+
+// statusClass buckets a status as "4xx", "5xx", etc. Requests that never got
+// a response are reported as "transport".
+func statusClass(code int) string {
+	if code == 0 {
+		return "transport"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
+
+func register[T prometheus.Collector](reg prometheus.Registerer, c T) T {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return c
+}
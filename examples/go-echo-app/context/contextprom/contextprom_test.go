@@ -0,0 +1,73 @@
+package contextprom
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/example/go-echo-app/context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/context/query":
+			json.NewEncoder(w).Encode(context.QueryResponse{})
+		case "/adr/missing":
+			http.NotFound(w, r)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	reg := prometheus.NewRegistry()
+	r := New(reg)
+	c := context.NewClient(srv.URL,
+		context.WithMetricsRecorder(r),
+		context.WithQueryCache(time.Minute),
+		context.WithRetry(context.RetryPolicy{MaxAttempts: 1}),
+	)
+	ctx := stdcontext.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := c.Query(ctx, context.QueryRequest{Query: "q"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, _ = c.GetADR(ctx, "missing")
+	_, _ = c.ListDomains(ctx)
+
+	srv.Close()
+	_, _ = c.ListDomains(ctx)
+
+	for _, tc := range []struct {
+		c    prometheus.Collector
+		want float64
+	}{
+		{r.requests.WithLabelValues("Query", "success"), 1},
+		{r.requests.WithLabelValues("GetADR", "error"), 1},
+		{r.requests.WithLabelValues("ListDomains", "error"), 2},
+		{r.errors.WithLabelValues("GetADR", "4xx"), 1},
+		{r.errors.WithLabelValues("ListDomains", "5xx"), 1},
+		{r.errors.WithLabelValues("ListDomains", "transport"), 1},
+		{r.cache.WithLabelValues("Query", "hit"), 1},
+		{r.cache.WithLabelValues("Query", "miss"), 1},
+	} {
+		if got := testutil.ToFloat64(tc.c); got != tc.want {
+			t.Errorf("%s = %v, want %v", tc.c.(prometheus.Metric).Desc(), got, tc.want)
+		}
+	}
+	if n := testutil.CollectAndCount(r.latency); n != 3 {
+		t.Errorf("latency series = %d, want one per method", n)
+	}
+
+	// Registering again on the same registry shares the collectors.
+	if again := New(reg); again.requests != r.requests {
+		t.Error("second New did not reuse the registered collectors")
+	}
+}
@@ -0,0 +1,67 @@
+package contexttest
+
+import (
+	stdcontext "context"
+	"testing"
+
+	"github.com/example/go-echo-app/context"
+)
+
+func TestFakeServerFromFixture(t *testing.T) {
+	srv, err := NewFakeServerFromFixture("testdata/fixture.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	c := context.NewClient(srv.URL)
+	resp, err := c.Query(stdcontext.Background(), context.QueryRequest{
+		Query:   "email validation",
+		Domains: []string{"validation"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := srv.Fixture.Tagged("validation")
+	if len(resp.KeyDecisions) != len(want.ADRs) || resp.KeyDecisions[0].ID != want.ADRs[0].ID {
+		t.Fatalf("decisions = %+v, want %+v", resp.KeyDecisions, want.ADRs)
+	}
+	if len(resp.RecentChanges) != 1 || resp.RecentChanges[0].ID != "CHG-001" {
+		t.Fatalf("changes = %+v", resp.RecentChanges)
+	}
+
+	issue, err := c.GetFailure(stdcontext.Background(), "FAIL-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Pattern != context.PatternDatabaseError {
+		t.Fatalf("pattern = %q", issue.Pattern)
+	}
+}
+
+func TestMockServerRecordsRequests(t *testing.T) {
+	srv, mock := NewMockServer()
+	defer srv.Close()
+	mock.SetQueryResponse(context.QueryResponse{KeyDecisions: []context.Decision{{ID: "ADR-9"}}})
+
+	c := context.NewClient(srv.URL)
+	ctx := stdcontext.Background()
+	if err := c.CreateADR(ctx, context.ADRRequest{Title: "t", Decision: "d"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Query(ctx, context.QueryRequest{Query: "q"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if adrs := mock.ADRs(); len(adrs) != 1 || adrs[0].Title != "t" {
+		t.Errorf("ADRs() = %+v", adrs)
+	}
+	if qs := mock.Queries(); len(qs) != 1 || qs[0].Query != "q" {
+		t.Errorf("Queries() = %+v", qs)
+	}
+	if len(resp.KeyDecisions) != 1 || resp.KeyDecisions[0].ID != "ADR-9" {
+		t.Errorf("decisions = %+v", resp.KeyDecisions)
+	}
+}
@@ -0,0 +1,239 @@
+// Package contexttest provides in-process context engines for tests: a fake
+// server answering queries from fixture files, so suites share the same
+// ADRs, failures and changes, and a mock server that records what the
+// client sent.
+package contexttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/example/go-echo-app/context"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is the data a fake server answers from. Fixture files use the same
+// field names as the API, under the top-level keys adrs, failures and
+// changes.
+type Fixture struct {
+	ADRs     []context.Decision `json:"adrs"`
+	Failures []context.Issue    `json:"failures"`
+	Changes  []context.Change   `json:"changes"`
+}
+
+// LoadFixture reads a fixture from a .json, .yaml or .yml file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load fixture: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+	case ".yaml", ".yml":
+		// Round-trip through JSON so both formats share the json tags.
+		var v any
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("load fixture %s: %w", path, err)
+		}
+		if data, err = json.Marshal(v); err != nil {
+			return nil, fmt.Errorf("load fixture %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("load fixture %s: unsupported extension %q", path, ext)
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("load fixture %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Tagged returns the subset of f carrying tag, so tests can refer to
+// fixture records by tag rather than by ID.
+func (f *Fixture) Tagged(tag string) Fixture {
+	var out Fixture
+	for _, d := range f.ADRs {
+		if hasTag(d.Tags, tag) {
+			out.ADRs = append(out.ADRs, d)
+		}
+	}
+	for _, i := range f.Failures {
+		if hasTag(i.Tags, tag) {
+			out.Failures = append(out.Failures, i)
+		}
+	}
+	for _, c := range f.Changes {
+		if hasTag(c.Tags, tag) {
+			out.Changes = append(out.Changes, c)
+		}
+	}
+	return out
+}
+
+// FakeServer is an httptest.Server serving a Fixture.
+type FakeServer struct {
+	*httptest.Server
+	Fixture *Fixture
+}
+
+// NewFakeServerFromFixture loads path and starts a server answering queries
+// against it. Callers must Close the server.
+func NewFakeServerFromFixture(path string) (*FakeServer, error) {
+	f, err := LoadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewFakeServer(f), nil
+}
+
+// NewFakeServer starts a server answering queries against f.
+func NewFakeServer(f *Fixture) *FakeServer {
+	s := &FakeServer{Fixture: f}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *FakeServer) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case r.Method == http.MethodGet && path == "/health":
+		writeJSON(w, map[string]string{"status": "ok"})
+	case r.Method == http.MethodPost && path == "/context/query":
+		var req context.QueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, s.query(req))
+	case r.Method == http.MethodGet && path == "/adr":
+		adrs := s.Fixture.ADRs
+		if tags := r.URL.Query().Get("tags"); tags != "" {
+			adrs = nil
+			for _, d := range s.Fixture.ADRs {
+				if hasAnyTag(d.Tags, strings.Split(tags, ",")) {
+					adrs = append(adrs, d)
+				}
+			}
+		}
+		writeJSON(w, nonNil(adrs))
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/adr/"):
+		id := strings.TrimPrefix(path, "/adr/")
+		for _, d := range s.Fixture.ADRs {
+			if d.ID == id {
+				writeJSON(w, map[string]any{"adr": d})
+				return
+			}
+		}
+		http.NotFound(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/failure/"):
+		id := strings.TrimPrefix(path, "/failure/")
+		for _, i := range s.Fixture.Failures {
+			if i.ID == id {
+				writeJSON(w, map[string]any{"failure": i})
+				return
+			}
+		}
+		http.NotFound(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// query scores each record by the fraction of query terms found in its
+// title, body or tags. Domains must match a tag when given. A record's own
+// fixture score, if set, takes precedence.
+func (s *FakeServer) query(req context.QueryRequest) context.QueryResponse {
+	terms := strings.Fields(strings.ToLower(req.Query))
+	keep := func(tags []string, text ...string) (float64, bool) {
+		if len(req.Domains) > 0 && !hasAnyTag(tags, req.Domains) {
+			return 0, false
+		}
+		score := match(terms, tags, text...)
+		return score, score > 0 && score >= req.MinScore
+	}
+
+	resp := context.QueryResponse{
+		KeyDecisions:  []context.Decision{},
+		KnownIssues:   []context.Issue{},
+		RecentChanges: []context.Change{},
+	}
+	for _, d := range s.Fixture.ADRs {
+		if score, ok := keep(d.Tags, d.Title, d.Decision); ok {
+			if d.Score == 0 {
+				d.Score = score
+			}
+			resp.KeyDecisions = append(resp.KeyDecisions, d)
+		}
+	}
+	for _, i := range s.Fixture.Failures {
+		if _, ok := keep(i.Tags, i.Title, i.RootCause, i.Resolution); ok {
+			resp.KnownIssues = append(resp.KnownIssues, i)
+		}
+	}
+	for _, c := range s.Fixture.Changes {
+		if _, ok := keep(c.Tags, c.Title); ok {
+			resp.RecentChanges = append(resp.RecentChanges, c)
+		}
+	}
+
+	sort.SliceStable(resp.KeyDecisions, func(i, j int) bool {
+		return resp.KeyDecisions[i].Score > resp.KeyDecisions[j].Score
+	})
+	if req.MaxItems > 0 && len(resp.KeyDecisions) > req.MaxItems {
+		resp.KeyDecisions = resp.KeyDecisions[:req.MaxItems]
+	}
+	resp.TotalItems = len(resp.KeyDecisions) + len(resp.KnownIssues) + len(resp.RecentChanges)
+	return resp
+}
+
+func match(terms, tags []string, text ...string) float64 {
+	if len(terms) == 0 {
+		return 1
+	}
+	hay := strings.ToLower(strings.Join(append(text, tags...), " "))
+	n := 0
+	for _, t := range terms {
+		if strings.Contains(hay, t) {
+			n++
+		}
+	}
+	return float64(n) / float64(len(terms))
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyTag(tags, want []string) bool {
+	for _, w := range want {
+		if hasTag(tags, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,104 @@
+package contexttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/example/go-echo-app/context"
+)
+
+// Mock records what a client sent to a server from NewMockServer and lets
+// tests stub query results. It is safe for concurrent use.
+type Mock struct {
+	mu       sync.Mutex
+	adrs     []context.ADRRequest
+	failures []context.FailureRequest
+	queries  []context.QueryRequest
+	query    context.QueryResponse
+}
+
+// NewMockServer starts a server that accepts ADRs and failures, answers
+// health checks, and replies to queries with the response set by
+// SetQueryResponse (empty by default). Callers must Close the server.
+func NewMockServer() (*httptest.Server, *Mock) {
+	m := &Mock{query: context.QueryResponse{
+		KeyDecisions:  []context.Decision{},
+		KnownIssues:   []context.Issue{},
+		RecentChanges: []context.Change{},
+	}}
+	return httptest.NewServer(http.HandlerFunc(m.serve)), m
+}
+
+// SetQueryResponse sets the response to every subsequent query.
+func (m *Mock) SetQueryResponse(resp context.QueryResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.query = resp
+}
+
+// ADRs returns the ADRs received so far, in order.
+func (m *Mock) ADRs() []context.ADRRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]context.ADRRequest(nil), m.adrs...)
+}
+
+// Failures returns the failures received so far, in order.
+func (m *Mock) Failures() []context.FailureRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]context.FailureRequest(nil), m.failures...)
+}
+
+// Queries returns the queries received so far, in order.
+func (m *Mock) Queries() []context.QueryRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]context.QueryRequest(nil), m.queries...)
+}
+
+func (m *Mock) serve(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case r.Method == http.MethodGet && path == "/health":
+		writeJSON(w, map[string]string{"status": "ok"})
+	case r.Method == http.MethodPost && path == "/adr":
+		var req context.ADRRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		m.adrs = append(m.adrs, req)
+		writeJSON(w, map[string]string{"id": fmt.Sprintf("adr-%d", len(m.adrs))})
+	case r.Method == http.MethodPost && path == "/failure":
+		var req context.FailureRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		m.failures = append(m.failures, req)
+		writeJSON(w, map[string]string{"id": fmt.Sprintf("failure-%d", len(m.failures))})
+	case r.Method == http.MethodPost && path == "/context/query":
+		var req context.QueryRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		m.queries = append(m.queries, req)
+		writeJSON(w, m.query)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+// Package contextws provides the WebSocket dialer for
+// Client.SubscribeQuery. It lives in its own package so the core client
+// does not depend on gorilla/websocket.
+//
+//	c := context.NewClient(url, context.WithWebSocket(contextws.Dialer{}))
+package contextws
+
+import (
+	stdcontext "context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/example/go-echo-app/context"
+	"github.com/gorilla/websocket"
+)
+
+const defaultPingInterval = 30 * time.Second
+
+// Dialer implements context.WebSocketDialer. Each connection it opens pings
+// the server every PingInterval and is dropped if nothing, pong or data,
+// arrives for two intervals.
+type Dialer struct {
+	// PingInterval defaults to 30s.
+	PingInterval time.Duration
+}
+
+// Dial opens a WebSocket to url. A refused handshake is returned as a
+// *context.APIError carrying the server's status and body, which
+// SubscribeQuery redacts with the client's redactor.
+func (d Dialer) Dial(ctx stdcontext.Context, url string, header http.Header) (context.WebSocketConn, error) {
+	ws, resp, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+	if err != nil {
+		if resp != nil && errors.Is(err, websocket.ErrBadHandshake) {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+			resp.Body.Close()
+			return nil, &context.APIError{
+				StatusCode: resp.StatusCode,
+				RawBody:    string(body),
+				RequestID:  resp.Header.Get("X-Request-ID"),
+			}
+		}
+		return nil, err
+	}
+
+	interval := d.PingInterval
+	if interval <= 0 {
+		interval = defaultPingInterval
+	}
+	c := &conn{ws: ws, wait: 2 * interval, done: make(chan struct{})}
+	ws.SetReadDeadline(time.Now().Add(c.wait))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(c.wait))
+	})
+	go c.keepalive(interval)
+	return c, nil
+}
+
+type conn struct {
+	ws   *websocket.Conn
+	wait time.Duration // read deadline after each frame
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func (c *conn) keepalive(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			// WriteControl may run alongside WriteMessage.
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(interval)); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *conn) ReadMessage() ([]byte, error) {
+	_, msg, err := c.ws.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	c.ws.SetReadDeadline(time.Now().Add(c.wait))
+	return msg, nil
+}
+
+func (c *conn) WriteMessage(data []byte) error {
+	return c.ws.WriteMessage(websocket.TextMessage, data)
+}
+
+// Close closes the socket without a close handshake, so it can't block on
+// a dead peer. Later calls do nothing.
+func (c *conn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.ws.Close()
+	})
+	return err
+}
@@ -0,0 +1,104 @@
+package contextws
+
+import (
+	stdcontext "context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/example/go-echo-app/context"
+	"github.com/gorilla/websocket"
+)
+
+func TestSubscribeQuery(t *testing.T) {
+	var pings atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws/query" || r.Header.Get("X-Request-ID") == "" {
+			t.Errorf("handshake %s, request id %q", r.URL.Path, r.Header.Get("X-Request-ID"))
+		}
+		ws, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer ws.Close()
+		ws.SetPingHandler(func(data string) error {
+			pings.Add(1)
+			return ws.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		})
+		_, sub, err := ws.ReadMessage()
+		if err != nil || !strings.Contains(string(sub), `"query":"routing"`) {
+			t.Errorf("subscription = %s, %v", sub, err)
+		}
+		ws.WriteMessage(websocket.TextMessage, []byte(`{"id":"a","title":"A"}`))
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+	c := context.NewClient(srv.URL, context.WithWebSocket(Dialer{PingInterval: 10 * time.Millisecond}))
+
+	ctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	defer cancel()
+	decisions, errc := c.SubscribeQuery(ctx, context.QueryRequest{Query: "routing"})
+	if d := <-decisions; d.ID != "a" {
+		t.Fatalf("decision = %+v", d)
+	}
+	// Pongs keep the connection open well past the read deadline.
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	if _, ok := <-decisions; ok {
+		t.Error("decisions not closed")
+	}
+	if err, ok := <-errc; ok {
+		t.Errorf("err = %v", err)
+	}
+	if pings.Load() < 3 {
+		t.Errorf("pings = %d", pings.Load())
+	}
+}
+
+func TestDeadPeer(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+		// Never read, so pings go unanswered.
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	conn, err := Dialer{PingInterval: 10 * time.Millisecond}.Dial(stdcontext.Background(), url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.ReadMessage(); err == nil {
+		t.Error("read succeeded on a silent peer")
+	}
+}
+
+func TestRefusedHandshake(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no subscriptions for you", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	_, err := Dialer{}.Dial(stdcontext.Background(), url, nil)
+	var apiErr *context.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || !strings.Contains(apiErr.RawBody, "no subscriptions") {
+		t.Errorf("err = %v", err)
+	}
+}
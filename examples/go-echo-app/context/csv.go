@@ -0,0 +1,43 @@
+package context
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteCSV writes the response as CSV with a header row and one row per
+// decision, issue and change, in that order. The columns are kind, id,
+// title, score and tags, with tags joined by "|".
+func (r *QueryResponse) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	row := func(kind Kind, id, title string, score float64, tags []string) error {
+		return cw.Write([]string{
+			string(kind), id, title,
+			strconv.FormatFloat(score, 'f', -1, 64),
+			strings.Join(tags, "|"),
+		})
+	}
+
+	if err := cw.Write([]string{"kind", "id", "title", "score", "tags"}); err != nil {
+		return err
+	}
+	for _, d := range r.KeyDecisions {
+		if err := row(KindDecision, d.ID, d.Title, d.Score, d.Tags); err != nil {
+			return err
+		}
+	}
+	for _, i := range r.KnownIssues {
+		if err := row(KindIssue, i.ID, i.Title, i.Score, i.Tags); err != nil {
+			return err
+		}
+	}
+	for _, c := range r.RecentChanges {
+		if err := row(KindChange, c.ID, c.Title, c.Score, c.Tags); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
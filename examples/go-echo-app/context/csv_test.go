@@ -0,0 +1,27 @@
+package context
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	resp := &QueryResponse{
+		KeyDecisions:  []Decision{{ID: "adr-1", Title: `Use "Echo", not Gin`, Score: 0.95, Tags: []string{"go", "web"}}},
+		KnownIssues:   []Issue{{ID: "F-1", Title: "pool\nexhaustion", Score: 0.5}},
+		RecentChanges: []Change{{ID: "c-1", Title: "bump", Score: 1, Tags: []string{"deps"}}},
+	}
+	var b strings.Builder
+	if err := resp.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `kind,id,title,score,tags
+decision,adr-1,"Use ""Echo"", not Gin",0.95,go|web
+issue,F-1,"pool
+exhaustion",0.5,
+change,c-1,bump,1,deps
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
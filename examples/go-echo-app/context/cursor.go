@@ -0,0 +1,98 @@
+package context
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+)
+
+// Query results are paged by keyset rather than offset: a cursor records the
+// score and ID of the last decision on a page, and the next page starts
+// strictly after that position in (score desc, ID asc) order. Decisions added
+// or removed between pages therefore never cause an item to be skipped or
+// repeated; new decisions that sort before the cursor are simply not seen by
+// that iteration.
+
+const defaultPageSize = 50
+
+type cursor struct {
+	Score float64 `json:"s"`
+	ID    string  `json:"id"`
+}
+
+func encodeCursor(d Decision) string {
+	b, _ := json.Marshal(cursor{Score: d.Score, ID: d.ID})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor reports whether s is a cursor this client issued. Anything
+// else is the server's own opaque cursor and is passed through untouched.
+func decodeCursor(s string) (cursor, bool) {
+	var cur cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(b, &cur) != nil || cur.ID == "" {
+		return cursor{}, false
+	}
+	return cur, true
+}
+
+// keysetLess orders decisions for paging: highest score first, ties broken
+// by ID so the order is total.
+func keysetLess(a, b Decision) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	return a.ID < b.ID
+}
+
+// page applies req's cursor and MaxItems to decisions the server returned,
+// for servers that ignore either, and fills in NextCursor.
+func (r *QueryResponse) page(req QueryRequest) {
+	paging := req.Cursor != "" || req.MaxItems > 0
+	if !paging || req.SortBy != "" {
+		return
+	}
+	sort.SliceStable(r.KeyDecisions, func(i, j int) bool {
+		return keysetLess(r.KeyDecisions[i], r.KeyDecisions[j])
+	})
+
+	if cur, ok := decodeCursor(req.Cursor); ok {
+		after := Decision{Score: cur.Score, ID: cur.ID}
+		kept := r.KeyDecisions[:0]
+		for _, d := range r.KeyDecisions {
+			if keysetLess(after, d) {
+				kept = append(kept, d)
+			}
+		}
+		r.KeyDecisions = kept
+	}
+
+	if req.MaxItems > 0 && len(r.KeyDecisions) >= req.MaxItems {
+		r.KeyDecisions = r.KeyDecisions[:req.MaxItems]
+		if r.NextCursor == "" {
+			r.NextCursor = encodeCursor(r.KeyDecisions[len(r.KeyDecisions)-1])
+		}
+	}
+}
+
+// QueryAll follows NextCursor until the results are exhausted and returns
+// every decision. req.MaxItems sets the page size, defaulting to 50; see the
+// note on paging above for the stability guarantee.
+func (c *Client) QueryAll(ctx context.Context, req QueryRequest, opts ...CallOption) ([]Decision, error) {
+	if req.MaxItems <= 0 {
+		req.MaxItems = defaultPageSize
+	}
+	var all []Decision
+	for {
+		resp, err := c.Query(ctx, req, opts...)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, resp.KeyDecisions...)
+		if resp.NextCursor == "" || resp.NextCursor == req.Cursor || len(resp.KeyDecisions) == 0 {
+			return all, nil
+		}
+		req.Cursor = resp.NextCursor
+	}
+}
@@ -0,0 +1,63 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestQueryAllStableAcrossInserts(t *testing.T) {
+	decisions := []Decision{
+		{ID: "a", Score: 0.9}, {ID: "b", Score: 0.8}, {ID: "c", Score: 0.8},
+		{ID: "d", Score: 0.5}, {ID: "e", Score: 0.1},
+	}
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Ignores max_items and cursor, and gains an item after each page.
+		calls++
+		decisions = append(decisions, Decision{ID: fmt.Sprintf("new%d", calls), Score: 0.95})
+		_ = json.NewEncoder(w).Encode(QueryResponse{KeyDecisions: decisions})
+	}))
+	defer srv.Close()
+
+	all, err := NewClient(srv.URL).QueryAll(context.Background(), QueryRequest{Query: "q", MaxItems: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, d := range all {
+		ids = append(ids, d.ID)
+	}
+	if got := strings.Join(ids, ","); got != "new1,a,b,c,d,e" {
+		t.Errorf("ids = %s", got)
+	}
+}
+
+func TestQueryAllServerCursor(t *testing.T) {
+	pages := map[string]QueryResponse{
+		"":             {KeyDecisions: []Decision{{ID: "ADR-1", Score: 0.9}, {ID: "ADR-2", Score: 0.8}}, NextCursor: "opaque:page2"},
+		"opaque:page2": {KeyDecisions: []Decision{{ID: "ADR-3", Score: 0.7}}},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req QueryRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		resp, ok := pages[req.Cursor]
+		if !ok {
+			t.Errorf("unexpected cursor %q", req.Cursor)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	all, err := NewClient(srv.URL).QueryAll(context.Background(), QueryRequest{Query: "q", MaxItems: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || all[2].ID != "ADR-3" {
+		t.Errorf("decisions = %+v", all)
+	}
+}
@@ -0,0 +1,72 @@
+package context
+
+// WithDedup makes Query call Dedup on every response. Off by default.
+func WithDedup(enabled bool) Option {
+	return func(c *Client) {
+		c.dedup = enabled
+	}
+}
+
+// DedupKey returns the identity of an item for DedupBy. v is a Decision,
+// Issue or Change according to kind. Items with an empty key are never
+// treated as duplicates.
+type DedupKey func(kind Kind, v any) string
+
+// Dedup removes decisions, issues and changes that share an ID, across
+// slices as well as within them, keeping the highest-scored instance.
+func (r *QueryResponse) Dedup() {
+	r.DedupBy(func(_ Kind, v any) string {
+		switch v := v.(type) {
+		case Decision:
+			return v.ID
+		case Issue:
+			return v.ID
+		case Change:
+			return v.ID
+		}
+		return ""
+	})
+}
+
+// DedupBy is Dedup with a caller-supplied identity, for records that refer
+// to the same event under different IDs. Among items with equal keys the
+// highest score wins; on a tie, decisions beat issues beat changes, then
+// the earlier item wins. Order within each slice is preserved.
+func (r *QueryResponse) DedupBy(key DedupKey) {
+	type item struct {
+		kind  Kind
+		index int
+		score float64
+	}
+	best := make(map[string]item)
+	drop := map[Kind]map[int]bool{KindDecision: {}, KindIssue: {}, KindChange: {}}
+	add := func(kind Kind, i int, score float64, v any) {
+		k := key(kind, v)
+		if k == "" {
+			return
+		}
+		prev, seen := best[k]
+		switch {
+		case !seen:
+			best[k] = item{kind, i, score}
+		case score > prev.score:
+			drop[prev.kind][prev.index] = true
+			best[k] = item{kind, i, score}
+		default:
+			drop[kind][i] = true
+		}
+	}
+	for i, d := range r.KeyDecisions {
+		add(KindDecision, i, d.Score, d)
+	}
+	for i, is := range r.KnownIssues {
+		add(KindIssue, i, is.Score, is)
+	}
+	for i, c := range r.RecentChanges {
+		add(KindChange, i, c.Score, c)
+	}
+
+	r.KeyDecisions = keep(r.KeyDecisions, drop[KindDecision])
+	r.KnownIssues = keep(r.KnownIssues, drop[KindIssue])
+	r.RecentChanges = keep(r.RecentChanges, drop[KindChange])
+}
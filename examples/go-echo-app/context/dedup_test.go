@@ -0,0 +1,71 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDedup(t *testing.T) {
+	resp := QueryResponse{
+		KeyDecisions: []Decision{
+			{ID: "evt-1", Title: "a", Score: 0.5},
+			{ID: "ADR-2", Title: "b", Score: 0.8},
+			{ID: "ADR-2", Title: "b again", Score: 0.6},
+		},
+		KnownIssues: []Issue{{ID: "evt-1", Title: "a issue", Score: 0.9}},
+		RecentChanges: []Change{
+			{ID: "evt-1", Title: "a change", Score: 0.7},
+			{ID: "CHG-3", Title: "c", Score: 0.1},
+		},
+	}
+	resp.Dedup()
+	if len(resp.KeyDecisions) != 1 || resp.KeyDecisions[0].Title != "b" {
+		t.Errorf("decisions = %+v", resp.KeyDecisions)
+	}
+	if len(resp.KnownIssues) != 1 || resp.KnownIssues[0].ID != "evt-1" {
+		t.Errorf("issues = %+v", resp.KnownIssues)
+	}
+	if len(resp.RecentChanges) != 1 || resp.RecentChanges[0].ID != "CHG-3" {
+		t.Errorf("changes = %+v", resp.RecentChanges)
+	}
+
+	// A custom key merges records that share a title but not an ID.
+	resp = QueryResponse{
+		KeyDecisions:  []Decision{{ID: "ADR-1", Title: "Cache", Score: 0.9}},
+		RecentChanges: []Change{{ID: "CHG-1", Title: "cache", Score: 0.4}},
+	}
+	resp.DedupBy(func(_ Kind, v any) string {
+		switch v := v.(type) {
+		case Decision:
+			return strings.ToLower(v.Title)
+		case Change:
+			return strings.ToLower(v.Title)
+		}
+		return ""
+	})
+	if len(resp.KeyDecisions) != 1 || len(resp.RecentChanges) != 0 {
+		t.Errorf("DedupBy = %+v", resp)
+	}
+}
+
+func TestWithDedup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(QueryResponse{
+			KeyDecisions:  []Decision{{ID: "X-1", Score: 0.2}},
+			RecentChanges: []Change{{ID: "X-1", Score: 0.3}},
+		})
+	}))
+	defer srv.Close()
+
+	resp, err := NewClient(srv.URL, WithDedup(true)).Query(context.Background(), QueryRequest{Query: "q"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.KeyDecisions) != 0 || len(resp.RecentChanges) != 1 {
+		t.Errorf("response = %+v", resp)
+	}
+}
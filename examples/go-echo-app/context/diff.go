@@ -0,0 +1,108 @@
+package context
+
+import (
+	"context"
+	"slices"
+)
+
+// ADRDiff is what changed in an ADR between two versions, as computed by
+// DiffADR. It marshals to JSON for rendering elsewhere.
+type ADRDiff struct {
+	ID          string `json:"id"`
+	FromVersion int    `json:"from_version"`
+	ToVersion   int    `json:"to_version"`
+	// Title, Context and Decision are nil when the text is unchanged.
+	Title       *TextChange `json:"title,omitempty"`
+	Context     *TextChange `json:"context,omitempty"`
+	Decision    *TextChange `json:"decision,omitempty"`
+	TagsAdded   []string    `json:"tags_added,omitempty"`
+	TagsRemoved []string    `json:"tags_removed,omitempty"`
+	// OptionsChanged names options present in both versions whose pros
+	// differ.
+	OptionsAdded   []string `json:"options_added,omitempty"`
+	OptionsRemoved []string `json:"options_removed,omitempty"`
+	OptionsChanged []string `json:"options_changed,omitempty"`
+}
+
+// TextChange is a text field's value before and after.
+type TextChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Empty reports whether the two versions are the same in every field the
+// diff covers.
+func (d ADRDiff) Empty() bool {
+	return d.Title == nil && d.Context == nil && d.Decision == nil &&
+		len(d.TagsAdded) == 0 && len(d.TagsRemoved) == 0 &&
+		len(d.OptionsAdded) == 0 && len(d.OptionsRemoved) == 0 && len(d.OptionsChanged) == 0
+}
+
+// DiffADR fetches versions fromVersion and toVersion of ADR id with
+// GetADRVersion and reports what changed between them. The diff is computed
+// client-side.
+func (c *Client) DiffADR(ctx context.Context, id string, fromVersion, toVersion int, opts ...CallOption) (ADRDiff, error) {
+	from, err := c.GetADRVersion(ctx, id, fromVersion, opts...)
+	if err != nil {
+		return ADRDiff{}, err
+	}
+	to, err := c.GetADRVersion(ctx, id, toVersion, opts...)
+	if err != nil {
+		return ADRDiff{}, err
+	}
+	d := diffDecisions(*from, *to)
+	d.ID, d.FromVersion, d.ToVersion = id, fromVersion, toVersion
+	return d, nil
+}
+
+func diffDecisions(from, to Decision) ADRDiff {
+	var d ADRDiff
+	d.Title = textChange(from.Title, to.Title)
+	d.Context = textChange(from.Context, to.Context)
+	d.Decision = textChange(from.Decision, to.Decision)
+	d.TagsAdded, d.TagsRemoved = setDiff(from.Tags, to.Tags)
+
+	fromOpts := orderedOptions(from.OptionsConsidered, from.OptionsConsideredOrdered)
+	before := optionsMap(fromOpts)
+	after := orderedOptions(to.OptionsConsidered, to.OptionsConsideredOrdered)
+	seen := make(map[string]bool, len(after))
+	for _, o := range after {
+		seen[o.Name] = true
+		pros, ok := before[o.Name]
+		switch {
+		case !ok:
+			d.OptionsAdded = append(d.OptionsAdded, o.Name)
+		case !slices.Equal(pros, o.Pros):
+			d.OptionsChanged = append(d.OptionsChanged, o.Name)
+		}
+	}
+	for _, o := range fromOpts {
+		if !seen[o.Name] {
+			d.OptionsRemoved = append(d.OptionsRemoved, o.Name)
+		}
+	}
+	return d
+}
+
+func textChange(from, to string) *TextChange {
+	if from == to {
+		return nil
+	}
+	return &TextChange{From: from, To: to}
+}
+
+// setDiff returns the items of b missing from a, and of a missing from b,
+// each in its original order.
+func setDiff(a, b []string) (added, removed []string) {
+	for _, s := range b {
+		if !slices.Contains(a, s) {
+			added = append(added, s)
+		}
+	}
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
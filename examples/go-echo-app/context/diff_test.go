@@ -0,0 +1,51 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiffADR(t *testing.T) {
+	versions := map[string]string{
+		"/adr/a/versions/1": `{"adr":{"id":"a","title":"Use gin","decision":"gin","tags":["go","http"],
+			"options_considered":{"gin":["fast"],"chi":["small"]}}}`,
+		"/adr/a/versions/2": `{"adr":{"id":"a","title":"Use echo","decision":"gin","tags":["go","web"],
+			"options_considered":{"gin":["fast","popular"],"echo":["simple"]}}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := versions[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	d, err := c.DiffADR(context.Background(), "a", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"a","from_version":1,"to_version":2,"title":{"from":"Use gin","to":"Use echo"},` +
+		`"tags_added":["web"],"tags_removed":["http"],"options_added":["echo"],"options_removed":["chi"],"options_changed":["gin"]}`
+	if string(raw) != want {
+		t.Errorf("diff = %s\nwant   %s", raw, want)
+	}
+
+	if d, err := c.DiffADR(context.Background(), "a", 2, 2); err != nil || !d.Empty() {
+		t.Errorf("same version: diff = %+v, err = %v", d, err)
+	}
+	var apiErr *APIError
+	if _, err := c.DiffADR(context.Background(), "a", 1, 3); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("missing version: err = %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// WithDNSCache caches the addresses each host resolves to for ttl and
+// spreads new connections across them round-robin, trying the next
+// address if one refuses. It saves a lookup per connection where DNS is
+// slow. Lookups and dials honor the request's context. It has no effect
+// together with WithHTTPClient or WithUnixSocket; ttl <= 0 disables it.
+func WithDNSCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.dnsCache = nil
+		if ttl > 0 {
+			d := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+			c.dnsCache = &dnsCache{
+				ttl:     ttl,
+				lookup:  net.DefaultResolver.LookupHost,
+				dial:    d.DialContext,
+				entries: make(map[string]*dnsEntry),
+			}
+		}
+	}
+}
+
+type dnsCache struct {
+	ttl    time.Duration
+	lookup func(ctx context.Context, host string) ([]string, error)
+	dial   func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	mu      sync.Mutex
+	entries map[string]*dnsEntry
+}
+
+type dnsEntry struct {
+	addrs   []string
+	expires time.Time
+	next    int
+}
+
+// dialContext is an http.Transport.DialContext that resolves through the
+// cache.
+func (d *dnsCache) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return d.dial(ctx, network, addr)
+	}
+	addrs, err := d.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, ip := range addrs {
+		conn, err := d.dial(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// resolve returns host's addresses starting from the next one in turn,
+// looking them up if the cached ones have expired.
+func (d *dnsCache) resolve(ctx context.Context, host string) ([]string, error) {
+	now := time.Now()
+	d.mu.Lock()
+	e, ok := d.entries[host]
+	d.mu.Unlock()
+	if !ok || !now.Before(e.expires) {
+		addrs, err := d.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		if len(addrs) == 0 {
+			return nil, &net.DNSError{Err: "no addresses", Name: host, IsNotFound: true}
+		}
+		e = &dnsEntry{addrs: addrs, expires: now.Add(d.ttl)}
+		d.mu.Lock()
+		d.entries[host] = e
+		d.mu.Unlock()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	start := e.next
+	e.next = (e.next + 1) % len(e.addrs)
+	return append(append([]string(nil), e.addrs[start:]...), e.addrs[:start]...), nil
+}
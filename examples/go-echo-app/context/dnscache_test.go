@@ -0,0 +1,80 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDNSCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"domains":[]}`))
+	}))
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	c := NewClient("http://context.internal:"+port, WithDNSCache(time.Minute))
+	var lookups atomic.Int32
+	c.dnsCache.lookup = func(ctx context.Context, host string) ([]string, error) {
+		lookups.Add(1)
+		if host != "context.internal" {
+			t.Errorf("lookup %s", host)
+		}
+		// The first address refuses connections, so every dial falls
+		// through to the second at some point.
+		return []string{"127.0.0.2", "127.0.0.1"}, nil
+	}
+	var mu sync.Mutex
+	var dialed []string
+	dial := c.dnsCache.dial
+	c.dnsCache.dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		mu.Lock()
+		dialed = append(dialed, addr)
+		mu.Unlock()
+		if strings.HasPrefix(addr, "127.0.0.2:") {
+			return nil, errors.New("connection refused")
+		}
+		return dial(ctx, network, addr)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.ListDomains(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		c.client.CloseIdleConnections()
+	}
+	if n := lookups.Load(); n != 1 {
+		t.Errorf("%d lookups, want 1", n)
+	}
+	mu.Lock()
+	got := strings.Join(dialed, ",")
+	mu.Unlock()
+	want := strings.Join([]string{"127.0.0.2:" + port, "127.0.0.1:" + port, "127.0.0.1:" + port, "127.0.0.2:" + port, "127.0.0.1:" + port}, ",")
+	if got != want {
+		t.Errorf("dialed %s, want %s", got, want)
+	}
+
+	// Expired entries are looked up again.
+	c.dnsCache.entries["context.internal"].expires = time.Now()
+	if _, err := c.ListDomains(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := lookups.Load(); n != 2 {
+		t.Errorf("%d lookups after expiry, want 2", n)
+	}
+
+	// A cancelled context stops the dial.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.client.CloseIdleConnections()
+	if _, err := c.ListDomains(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: err = %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Well-known domains. The server's list is authoritative; see ListDomains.
+const (
+	DomainUsers       = "users"
+	DomainValidation  = "validation"
+	DomainDatabase    = "database"
+	DomainAPI         = "api"
+	DomainSecurity    = "security"
+	DomainPerformance = "performance"
+)
+
+// UnknownDomainError lists requested domains the server doesn't know about.
+type UnknownDomainError struct {
+	Domains []string
+}
+
+func (e *UnknownDomainError) Error() string {
+	return "unknown domain(s): " + strings.Join(e.Domains, ", ")
+}
+
+// WithDomainValidation makes Query reject requests naming a domain outside
+// the set cached by LoadDomains. It has no effect until LoadDomains succeeds.
+func WithDomainValidation() Option {
+	return func(c *Client) {
+		c.validateDomains = true
+	}
+}
+
+// ListDomains fetches the domains the server knows about, sorted. Unlike
+// LoadDomains it doesn't touch the client's cached set.
+func (c *Client) ListDomains(ctx context.Context, opts ...CallOption) ([]string, error) {
+	var resp struct {
+		Domains []string `json:"domains"`
+	}
+	err := c.do(ctx, call{
+		op:     "ListDomains",
+		method: http.MethodGet,
+		path:   "/context/domains",
+		out:    &resp,
+		opts:   opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(resp.Domains)
+	return resp.Domains, nil
+}
+
+// LoadDomains fetches the server's known domains and caches them for
+// ValidateDomains. Call it at startup; it can be called again to refresh.
+// Once loaded, Query reports unknown domains through the WithLogger hook
+// even when WithDomainValidation is off.
+func (c *Client) LoadDomains(ctx context.Context) ([]string, error) {
+	domains, err := c.ListDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]struct{}, len(domains))
+	for _, d := range domains {
+		known[d] = struct{}{}
+	}
+	c.domainsMu.Lock()
+	c.domains = known
+	c.domainsMu.Unlock()
+
+	return domains, nil
+}
+
+// ValidateDomains returns an *UnknownDomainError if any of domains is not in
+// the set cached by LoadDomains. It returns nil if nothing has been loaded.
+func (c *Client) ValidateDomains(domains []string) error {
+	c.domainsMu.RLock()
+	defer c.domainsMu.RUnlock()
+	if c.domains == nil {
+		return nil
+	}
+
+	var unknown []string
+	for _, d := range domains {
+		if _, ok := c.domains[d]; !ok {
+			unknown = append(unknown, d)
+		}
+	}
+	if len(unknown) > 0 {
+		return &UnknownDomainError{Domains: unknown}
+	}
+	return nil
+}
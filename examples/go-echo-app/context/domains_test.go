@@ -0,0 +1,68 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestLoadDomainsCachesLookup(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		w.Write([]byte(`{"domains":["users","api"]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithDomainValidation())
+	if err := c.ValidateDomains([]string{"billing"}); err != nil {
+		t.Errorf("before LoadDomains: %v", err)
+	}
+	domains, err := c.LoadDomains(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(domains, []string{"api", "users"}) {
+		t.Errorf("domains = %v", domains)
+	}
+
+	if err := c.ValidateDomains([]string{DomainUsers, DomainAPI}); err != nil {
+		t.Errorf("known domains: %v", err)
+	}
+	err = c.ValidateDomains([]string{DomainUsers, "billing"})
+	var ude *UnknownDomainError
+	if !errors.As(err, &ude) || !reflect.DeepEqual(ude.Domains, []string{"billing"}) {
+		t.Errorf("unknown domain: %v", err)
+	}
+
+	// Query rejects the unknown domain from the cache, without a request.
+	if _, err := c.Query(context.Background(), QueryRequest{Query: "q", Domains: []string{"billing"}}); !errors.As(err, &ude) {
+		t.Errorf("Query err = %v", err)
+	}
+	if !reflect.DeepEqual(requests, []string{"/context/domains"}) {
+		t.Errorf("requests = %v", requests)
+	}
+}
+
+func TestListDomains(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"domains":["validation","users","billing"]}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	domains, err := c.ListDomains(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(domains, []string{"billing", DomainUsers, DomainValidation}) {
+		t.Errorf("domains = %v", domains)
+	}
+	// Unlike LoadDomains it leaves validation off.
+	if err := c.ValidateDomains([]string{"anything"}); err != nil {
+		t.Errorf("ValidateDomains after ListDomains: %v", err)
+	}
+}
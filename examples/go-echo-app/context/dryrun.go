@@ -0,0 +1,49 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrDryRunUnsupported is returned by a write made WithDryRun(true) when
+// the server does not advertise dry-run support. Nothing is sent, since a
+// server that ignored X-Dry-Run would apply the write.
+var ErrDryRunUnsupported = errors.New("server does not support dry runs")
+
+// WithDryRun(true) sends a write with X-Dry-Run: true, asking the server to
+// validate it and report the IDs it would assign without persisting
+// anything. The call returns what the server simulated, as it would for a
+// real write. Dry-run writes are never queued or buffered. The client
+// checks Capabilities first and fails with ErrDryRunUnsupported rather
+// than risk a real write. It has no effect on reads.
+func WithDryRun(on bool) CallOption {
+	return func(o *callOptions) {
+		o.dryRun = on
+	}
+}
+
+// mutates reports whether cl changes server state.
+func (cl call) mutates() bool {
+	return !cl.read && cl.method != http.MethodGet && cl.method != http.MethodHead
+}
+
+// checkDryRun returns ErrDryRunUnsupported unless the server supports dry
+// runs, fetching its capabilities on first use.
+func (c *Client) checkDryRun(ctx context.Context) error {
+	caps, ok := c.knownCapabilities()
+	if !ok {
+		var err error
+		caps, err = c.Capabilities(ctx)
+		if isUnsupported(err) {
+			return ErrDryRunUnsupported
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !caps.DryRun {
+		return ErrDryRunUnsupported
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDryRun(t *testing.T) {
+	for _, supported := range []bool{true, false} {
+		var writes, dryRuns atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/capabilities":
+				if !supported {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(`{"dry_run":true}`))
+			case r.Method == http.MethodGet:
+				if r.Header.Get("X-Dry-Run") != "" {
+					t.Error("read sent X-Dry-Run")
+				}
+				w.Write([]byte(`[]`))
+			default:
+				writes.Add(1)
+				if r.Header.Get("X-Dry-Run") == "true" {
+					dryRuns.Add(1)
+				}
+				w.Write([]byte(`{"id":"sim-1"}`))
+			}
+		}))
+		c := NewClient(srv.URL, WithWriteQueue(t.TempDir()), WithFailureBuffer(10, time.Hour))
+
+		id, err := c.CreateChange(context.Background(), ChangeRequest{Type: ChangeFix, Title: "t"}, WithDryRun(true))
+		ferr := c.RecordFailure(context.Background(), FailureRequest{Title: "t", RootCause: "rc", Severity: SeverityLow}, WithDryRun(true))
+		if _, lerr := c.ListChanges(context.Background(), ChangeFilter{}, WithDryRun(true)); lerr != nil {
+			t.Errorf("supported=%v: read: %v", supported, lerr)
+		}
+		if supported {
+			if err != nil || ferr != nil || id != "sim-1" || dryRuns.Load() != 2 {
+				t.Errorf("id = %q, err = %v, %v, dry runs = %d", id, err, ferr, dryRuns.Load())
+			}
+		} else {
+			if !errors.Is(err, ErrDryRunUnsupported) || !errors.Is(ferr, ErrDryRunUnsupported) {
+				t.Errorf("unsupported: err = %v, %v", err, ferr)
+			}
+			if n := writes.Load(); n != 0 || c.PendingCount() != 0 {
+				t.Errorf("unsupported: %d writes sent, %d queued", n, c.PendingCount())
+			}
+		}
+		c.Close(context.Background())
+		srv.Close()
+	}
+}
@@ -0,0 +1,63 @@
+package context
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithEmbeddingDimension makes Query and CountQuery reject a
+// QueryRequest.Embedding that doesn't have exactly n values, before
+// anything is sent. n <= 0, the default, accepts any length.
+func WithEmbeddingDimension(n int) Option {
+	return func(c *Client) {
+		c.embeddingDim = n
+	}
+}
+
+// checkEmbedding validates req.Embedding against WithEmbeddingDimension.
+func (c *Client) checkEmbedding(req QueryRequest) error {
+	if c.embeddingDim <= 0 || req.Embedding == nil || len(req.Embedding) == c.embeddingDim {
+		return nil
+	}
+	v := newValidator("QueryRequest")
+	v.add("embedding", fmt.Sprintf("has %d dimensions, want %d", len(req.Embedding), c.embeddingDim))
+	return v.err()
+}
+
+// An Embedder turns query text into a vector, for servers that only search
+// by embedding.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// WithEmbedder makes Query and CountQuery embed QueryRequest.Query with e
+// and send the vector, unless the request already carries an Embedding.
+// The text is still sent too. Without it, or with a nil e, nothing is
+// embedded client-side.
+func WithEmbedder(e Embedder) Option {
+	return func(c *Client) {
+		if e == nil {
+			e = noEmbedder{}
+		}
+		c.embedder = e
+	}
+}
+
+// noEmbedder is the default Embedder. A nil vector leaves the request as
+// it was.
+type noEmbedder struct{}
+
+func (noEmbedder) Embed(context.Context, string) ([]float32, error) { return nil, nil }
+
+// embed returns req with Embedding filled in by the client's Embedder if it
+// has none, then checks it against WithEmbeddingDimension.
+func (c *Client) embed(ctx context.Context, req QueryRequest) (QueryRequest, error) {
+	if req.Embedding == nil && req.Query != "" {
+		v, err := c.embedder.Embed(ctx, req.Query)
+		if err != nil {
+			return req, fmt.Errorf("embed query: %w", err)
+		}
+		req.Embedding = v
+	}
+	return req, c.checkEmbedding(req)
+}
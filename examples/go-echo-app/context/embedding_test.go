@@ -0,0 +1,92 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQueryEmbedding(t *testing.T) {
+	var got []float32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req QueryRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		got = req.Embedding
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, WithEmbeddingDimension(3))
+
+	if _, err := c.Query(context.Background(), NewQuery("").WithEmbedding([]float32{0.1, 0.2, 0.3}).Build()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[2] != 0.3 {
+		t.Errorf("embedding = %v", got)
+	}
+
+	got = nil
+	var verr *ValidationError
+	for _, v := range [][]float32{{0.1, 0.2}, {0.1, float32(math.NaN()), 0.3}} {
+		_, err := c.Query(context.Background(), QueryRequest{Embedding: v})
+		if !errors.As(err, &verr) || verr.Fields[0].Field != "embedding" {
+			t.Errorf("%v: err = %v", v, err)
+		}
+	}
+	if got != nil {
+		t.Error("invalid embedding was sent")
+	}
+}
+
+type fakeEmbedder struct {
+	calls int
+	err   error
+}
+
+func (e *fakeEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	e.calls++
+	return []float32{float32(len(text)), 1}, e.err
+}
+
+func TestEmbedder(t *testing.T) {
+	var got QueryRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = QueryRequest{}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+	e := &fakeEmbedder{}
+	c := NewClient(srv.URL, WithEmbedder(e), WithQueryCache(time.Minute))
+
+	if _, err := c.Query(context.Background(), QueryRequest{Query: "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Query != "abc" || len(got.Embedding) != 2 || got.Embedding[0] != 3 {
+		t.Errorf("sent %+v", got)
+	}
+	// A cache hit doesn't embed again, and neither does an explicit vector.
+	c.Query(context.Background(), QueryRequest{Query: "abc"})
+	c.Query(context.Background(), QueryRequest{Query: "abc", Embedding: []float32{9}})
+	if e.calls != 1 || got.Embedding[0] != 9 {
+		t.Errorf("calls = %d, sent %v", e.calls, got.Embedding)
+	}
+
+	if _, err := c.CountQuery(context.Background(), QueryRequest{Query: "abcd"}); err != nil || got.Embedding[0] != 4 {
+		t.Errorf("CountQuery: err = %v, sent %v", err, got.Embedding)
+	}
+
+	e.err = errors.New("model unavailable")
+	if _, err := c.Query(context.Background(), QueryRequest{Query: "x"}); !errors.Is(err, e.err) {
+		t.Errorf("err = %v", err)
+	}
+
+	// Without an Embedder nothing is embedded.
+	if _, err := NewClient(srv.URL).Query(context.Background(), QueryRequest{Query: "abc"}); err != nil || got.Embedding != nil {
+		t.Errorf("no embedder: err = %v, sent %v", err, got.Embedding)
+	}
+}
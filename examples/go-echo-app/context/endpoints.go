@@ -0,0 +1,203 @@
+package context
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// endpointFailThreshold consecutive failures open an endpoint's
+	// breaker for endpointCooldown.
+	endpointFailThreshold = 3
+	endpointCooldown      = 30 * time.Second
+)
+
+// BreakerState is the circuit breaker state of one endpoint of a client
+// made with NewClientWithEndpoints. Each endpoint has its own breaker, so
+// one failing region doesn't take traffic away from the others.
+type BreakerState string
+
+const (
+	// BreakerClosed endpoints are used in their configured order.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen endpoints failed endpointFailThreshold requests in a row
+	// and are tried only after every other endpoint until their cooldown
+	// ends.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen endpoints have finished their cooldown. One request
+	// at a time probes them first; the rest try them after closed ones. A
+	// success closes the breaker and a failure opens it again.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+type endpoint struct {
+	url string
+
+	mu        sync.Mutex
+	failures  int
+	downUntil time.Time
+	// probeUntil is when a half-open probe that never reported back stops
+	// blocking the next one.
+	probeUntil time.Time
+}
+
+// probe reports whether the caller may send the probe of a half-open
+// endpoint.
+func (e *endpoint) probe(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.downUntil.IsZero() || now.Before(e.downUntil) || now.Before(e.probeUntil) {
+		return false
+	}
+	e.probeUntil = now.Add(endpointCooldown)
+	return true
+}
+
+// release gives up a half-open probe without recording an outcome.
+func (e *endpoint) release() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.probeUntil = time.Time{}
+}
+
+func (e *endpoint) state(now time.Time) BreakerState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch {
+	case e.downUntil.IsZero():
+		return BreakerClosed
+	case now.Before(e.downUntil):
+		return BreakerOpen
+	default:
+		return BreakerHalfOpen
+	}
+}
+
+func (e *endpoint) record(failed bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.probeUntil = time.Time{}
+	if !failed {
+		e.failures = 0
+		e.downUntil = time.Time{}
+		return
+	}
+	// failures stays at or above the threshold until a success, so a
+	// half-open endpoint that fails again reopens at once.
+	e.failures++
+	if e.failures >= endpointFailThreshold {
+		e.downUntil = time.Now().Add(endpointCooldown)
+	}
+}
+
+// NewClientWithEndpoints returns a client that sends each request to the
+// first healthy endpoint in baseURLs and fails over to the next on a
+// connection error or 5xx, within the same call. Failover applies only to
+// calls that are safe to retry: reads, idempotent methods and keyed writes.
+// Each endpoint has a circuit breaker: one failing repeatedly is skipped
+// for a cooldown period (see BreakerState and EndpointHealth), and retries
+// configured with WithRetry start again from the first healthy endpoint.
+// BaseURL is set to the first endpoint.
+func NewClientWithEndpoints(baseURLs []string, opts ...Option) *Client {
+	first := ""
+	if len(baseURLs) > 0 {
+		first = baseURLs[0]
+	}
+	c := NewClient(first, opts...)
+	for _, u := range baseURLs {
+		c.endpoints = append(c.endpoints, &endpoint{url: strings.TrimRight(u, "/")})
+	}
+	return c
+}
+
+// endpointOrder returns a half-open endpoint due a probe, if any, then
+// endpoints with closed breakers in configured order, then the other
+// half-open ones, then open ones, which are a last resort rather than never
+// tried.
+func (c *Client) endpointOrder() []*endpoint {
+	now := time.Now()
+	order := make([]*endpoint, 0, len(c.endpoints))
+	var probe *endpoint
+	var halfOpen, open []*endpoint
+	for _, e := range c.endpoints {
+		switch e.state(now) {
+		case BreakerClosed:
+			order = append(order, e)
+		case BreakerHalfOpen:
+			if probe == nil && e.probe(now) {
+				probe = e
+			} else {
+				halfOpen = append(halfOpen, e)
+			}
+		default:
+			open = append(open, e)
+		}
+	}
+	if probe != nil {
+		order = append([]*endpoint{probe}, order...)
+	}
+	return append(append(order, halfOpen...), open...)
+}
+
+// EndpointHealth returns the breaker state of each endpoint, keyed by its
+// URL. It is nil for clients made with NewClient, which have no breakers.
+func (c *Client) EndpointHealth() map[string]BreakerState {
+	if len(c.endpoints) == 0 {
+		return nil
+	}
+	now := time.Now()
+	health := make(map[string]BreakerState, len(c.endpoints))
+	for _, e := range c.endpoints {
+		health[e.url] = e.state(now)
+	}
+	return health
+}
+
+// send makes one attempt of cl, failing over across endpoints if the client
+// has several and failover is true.
+func (c *Client) send(ctx context.Context, cl call, body []byte, key string, failover bool) (*http.Request, *http.Response, error) {
+	eps := c.endpointOrder()
+	if len(eps) == 0 {
+		eps = []*endpoint{nil}
+	} else if !failover {
+		eps = eps[:1]
+	}
+
+	for i, ep := range eps {
+		if ep != nil {
+			cl.base = ep.url
+		}
+		req, err := c.newRequest(ctx, cl, body)
+		if err != nil {
+			return nil, nil, err
+		}
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+
+		resp, err := c.roundTrip(req, c.hedgeable(cl))
+		failed := err != nil || resp.StatusCode >= 500
+		switch {
+		case ep == nil:
+		case err != nil && ctx.Err() != nil:
+			// The call was canceled or ran out of time, which says
+			// nothing about the endpoint.
+			ep.release()
+		default:
+			ep.record(failed)
+		}
+		if failed && i < len(eps)-1 && ctx.Err() == nil {
+			if resp != nil {
+				_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+				resp.Body.Close()
+			}
+			continue
+		}
+		return req, resp, err
+	}
+	panic("unreachable")
+}
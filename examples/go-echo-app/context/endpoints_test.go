@@ -0,0 +1,130 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEndpointFailover(t *testing.T) {
+	var primaryCalls int
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"key_decisions":[{"id":"from-secondary"}]}`)
+	}))
+	defer secondary.Close()
+
+	c := NewClientWithEndpoints([]string{primary.URL, secondary.URL})
+	for i := 0; i < endpointFailThreshold+2; i++ {
+		resp, err := c.Query(context.Background(), QueryRequest{Query: "q"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.KeyDecisions[0].ID != "from-secondary" {
+			t.Fatalf("decisions = %+v", resp.KeyDecisions)
+		}
+	}
+	if primaryCalls != endpointFailThreshold {
+		t.Errorf("primary called %d times, want %d before cooldown", primaryCalls, endpointFailThreshold)
+	}
+}
+
+func TestEndpointBreakers(t *testing.T) {
+	var down atomic.Bool
+	var primaryCalls atomic.Int32
+	down.Store(true)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer secondary.Close()
+
+	c := NewClientWithEndpoints([]string{primary.URL, secondary.URL})
+	query := func() {
+		t.Helper()
+		if _, err := c.Query(context.Background(), QueryRequest{Query: "q"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(want BreakerState) {
+		t.Helper()
+		h := c.EndpointHealth()
+		if h[primary.URL] != want || h[secondary.URL] != BreakerClosed {
+			t.Errorf("health = %v, want primary %s", h, want)
+		}
+	}
+	endCooldown := func() {
+		ep := c.endpoints[0]
+		ep.mu.Lock()
+		ep.downUntil = time.Now().Add(-time.Second)
+		ep.mu.Unlock()
+	}
+
+	check(BreakerClosed)
+	for i := 0; i < endpointFailThreshold; i++ {
+		query()
+	}
+	check(BreakerOpen)
+
+	// A failed probe reopens the breaker straight away.
+	endCooldown()
+	check(BreakerHalfOpen)
+	primaryCalls.Store(0)
+	query()
+	query()
+	if n := primaryCalls.Load(); n != 1 {
+		t.Errorf("half-open primary called %d times, want 1 probe", n)
+	}
+	check(BreakerOpen)
+
+	// A successful probe closes it.
+	endCooldown()
+	down.Store(false)
+	query()
+	check(BreakerClosed)
+
+	if h := NewClient(primary.URL).EndpointHealth(); h != nil {
+		t.Errorf("single endpoint health = %v", h)
+	}
+}
+
+func TestEndpointBreakerIgnoresCallerDeadline(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := NewClientWithEndpoints([]string{srv.URL})
+	for i := 0; i < endpointFailThreshold; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := c.Query(ctx, QueryRequest{Query: "q"})
+		cancel()
+		if err == nil {
+			t.Fatal("Query succeeded")
+		}
+	}
+	if got := c.EndpointHealth()[srv.URL]; got != BreakerClosed {
+		t.Errorf("breaker = %s after caller timeouts", got)
+	}
+}
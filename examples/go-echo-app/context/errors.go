@@ -0,0 +1,139 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const maxErrorBody = 4 << 10
+
+// APIError is returned when the server answers with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	RawBody    string
+	// RequestID is the X-Request-ID the server echoed, or else the one sent.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("unexpected status: %d (request id %s)", e.StatusCode, e.RequestID)
+	}
+	return fmt.Sprintf("unexpected status: %d", e.StatusCode)
+}
+
+// ErrConflict matches, via errors.Is, an *APIError for 412 Precondition
+// Failed: a WithIfMatch update lost a race with another writer.
+var ErrConflict = errors.New("conflict: resource was modified")
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrConflict && e.StatusCode == http.StatusPreconditionFailed
+}
+
+func (c *Client) newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	id := resp.Header.Get("X-Request-ID")
+	if id == "" && resp.Request != nil {
+		id = resp.Request.Header.Get("X-Request-ID")
+	}
+	return &APIError{StatusCode: resp.StatusCode, RawBody: c.redact(string(body)), RequestID: id}
+}
+
+// MarshalError reports a request body that could not be encoded. Field is
+// the JSON path of the offending field when it can be located.
+type MarshalError struct {
+	Op    string
+	Field string
+	Err   error
+}
+
+func (e *MarshalError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("%s: marshal request field %q: %v", e.Op, e.Field, e.Err)
+	}
+	return fmt.Sprintf("%s: marshal request: %v", e.Op, e.Err)
+}
+
+func (e *MarshalError) Unwrap() error { return e.Err }
+
+func newMarshalError(op string, v any, err error) *MarshalError {
+	return &MarshalError{Op: op, Field: badField(reflect.ValueOf(v), 0), Err: err}
+}
+
+const maxFieldDepth = 4
+
+// badField re-marshals the members of v one at a time to find the one that
+// fails, descending into structs and maps. It returns "" if v is not a
+// container or no single member fails on its own.
+func badField(v reflect.Value, depth int) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if depth >= maxFieldDepth {
+		return ""
+	}
+
+	fails := func(m reflect.Value) bool {
+		_, err := json.Marshal(m.Interface())
+		return err != nil
+	}
+	join := func(name string, m reflect.Value) string {
+		if sub := badField(m, depth+1); sub != "" {
+			return name + "." + sub
+		}
+		return name
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, skip := jsonFieldName(f)
+			if skip {
+				continue
+			}
+			if m := v.Field(i); fails(m) {
+				if f.Anonymous && f.Tag.Get("json") == "" {
+					// Embedded struct fields are promoted into the parent.
+					return badField(m, depth+1)
+				}
+				return join(name, m)
+			}
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			if m := v.MapIndex(k); fails(m) {
+				return join(fmt.Sprint(k.Interface()), m)
+			}
+		}
+	}
+	return ""
+}
+
+func jsonFieldName(f reflect.StructField) (name string, skip bool) {
+	if !f.IsExported() {
+		return "", true
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	if name, _, _ = strings.Cut(tag, ","); name == "" {
+		name = f.Name
+	}
+	return name, false
+}
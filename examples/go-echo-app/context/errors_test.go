@@ -0,0 +1,85 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDoAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"errors":{"title":["can't be blank"]}}`))
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL).Do(context.Background(), http.MethodPatch, "/adr/1", map[string]string{}, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.RawBody == "" {
+		t.Errorf("apiErr = %+v", apiErr)
+	}
+}
+
+type badMarshaler struct{}
+
+func (badMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("boom")
+}
+
+func TestMarshalErrorNamesField(t *testing.T) {
+	c := NewClient("http://unused.invalid")
+
+	tests := []struct {
+		name  string
+		in    any
+		field string
+		msg   string
+	}{
+		{
+			name: "struct field",
+			in: struct {
+				Bad badMarshaler `json:"bad_field"`
+			}{},
+			field: "bad_field",
+			msg:   `Do: marshal request field "bad_field": `,
+		},
+		{
+			name:  "nested map value",
+			in:    map[string]any{"ok": 1, "meta": map[string]any{"ch": make(chan int)}},
+			field: "meta.ch",
+			msg:   `Do: marshal request field "meta.ch": `,
+		},
+		{
+			name: "top level",
+			in:   make(chan int),
+			msg:  "Do: marshal request: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.Do(context.Background(), http.MethodPost, "/x", tt.in, nil)
+			var me *MarshalError
+			if !errors.As(err, &me) {
+				t.Fatalf("err = %v, want *MarshalError", err)
+			}
+			if me.Op != "Do" || me.Field != tt.field {
+				t.Errorf("Op, Field = %q, %q; want Do, %q", me.Op, me.Field, tt.field)
+			}
+			if !strings.HasPrefix(err.Error(), tt.msg) {
+				t.Errorf("message = %q, want prefix %q", err, tt.msg)
+			}
+		})
+	}
+
+	var jerr *json.UnsupportedTypeError
+	if err := c.Do(context.Background(), http.MethodPost, "/x", make(chan int), nil); !errors.As(err, &jerr) {
+		t.Errorf("err = %v, want wrapped *json.UnsupportedTypeError", err)
+	}
+}
@@ -0,0 +1,45 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// ExportDecisions pages through every decision matching req, as QueryAll
+// does, and writes each one to w as a line of JSON. Only one page is held
+// in memory at a time. If w has a Flush method, as a *bufio.Writer does,
+// it is flushed after each page. It returns the number of decisions
+// written, including when it stops early because ctx is done or a write or
+// query fails.
+func (c *Client) ExportDecisions(ctx context.Context, req QueryRequest, w io.Writer, opts ...CallOption) (int, error) {
+	if req.MaxItems <= 0 {
+		req.MaxItems = defaultPageSize
+	}
+	enc := json.NewEncoder(w)
+	n := 0
+	for {
+		resp, err := c.Query(ctx, req, opts...)
+		if err != nil {
+			return n, err
+		}
+		for _, d := range resp.KeyDecisions {
+			if err := ctx.Err(); err != nil {
+				return n, err
+			}
+			if err := enc.Encode(d); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if f, ok := w.(interface{ Flush() error }); ok {
+			if err := f.Flush(); err != nil {
+				return n, err
+			}
+		}
+		if resp.NextCursor == "" || resp.NextCursor == req.Cursor || len(resp.KeyDecisions) == 0 {
+			return n, nil
+		}
+		req.Cursor = resp.NextCursor
+	}
+}
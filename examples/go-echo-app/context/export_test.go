@@ -0,0 +1,60 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type flushCounter struct {
+	strings.Builder
+	flushes int
+}
+
+func (f *flushCounter) Flush() error {
+	f.flushes++
+	return nil
+}
+
+func TestExportDecisions(t *testing.T) {
+	decisions := []Decision{{ID: "a", Score: 0.9}, {ID: "b", Score: 0.8}, {ID: "c", Score: 0.5}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls, cancelAt atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == cancelAt.Load() {
+			cancel()
+		}
+		_ = json.NewEncoder(w).Encode(QueryResponse{KeyDecisions: decisions})
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	var out flushCounter
+	n, err := c.ExportDecisions(context.Background(), QueryRequest{Query: "q", MaxItems: 2}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if n != 3 || len(lines) != 3 || out.flushes != 2 {
+		t.Fatalf("n = %d, lines = %d, flushes = %d", n, len(lines), out.flushes)
+	}
+	var d Decision
+	if err := json.Unmarshal([]byte(lines[2]), &d); err != nil || d.ID != "c" {
+		t.Errorf("last line = %s (%v)", lines[2], err)
+	}
+
+	// Cancelling mid-export keeps what was written and reports the count.
+	calls.Store(0)
+	cancelAt.Store(2)
+	out = flushCounter{}
+	n, err = c.ExportDecisions(ctx, QueryRequest{Query: "q", MaxItems: 2}, &out)
+	if !errors.Is(err, context.Canceled) || n != 2 || strings.Count(out.String(), "\n") != 2 {
+		t.Errorf("cancelled: n = %d, err = %v, output %q", n, err, out.String())
+	}
+}
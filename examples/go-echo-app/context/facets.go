@@ -0,0 +1,32 @@
+package context
+
+import "sort"
+
+// Facet names used as keys of QueryResponse.Facets.
+const (
+	FacetTag    = "tag"
+	FacetDomain = "domain"
+)
+
+// FacetCount is the number of matching items with a given facet value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// TopTags returns the n most common tags among the matches, most common
+// first and then by name. n <= 0 returns them all. It is empty unless the
+// query set IncludeFacets and the server supports facets.
+func (r *QueryResponse) TopTags(n int) []FacetCount {
+	tags := append([]FacetCount(nil), r.Facets[FacetTag]...)
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Value < tags[j].Value
+	})
+	if n > 0 && len(tags) > n {
+		tags = tags[:n]
+	}
+	return tags
+}
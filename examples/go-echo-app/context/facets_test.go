@@ -0,0 +1,30 @@
+package context
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopTags(t *testing.T) {
+	r := &QueryResponse{Facets: map[string][]FacetCount{
+		FacetTag:    {{"http", 2}, {"db", 5}, {"auth", 2}, {"api", 1}},
+		FacetDomain: {{"users", 9}},
+	}}
+	// Ties on count are broken by name.
+	if got, want := r.TopTags(3), []FacetCount{{"db", 5}, {"auth", 2}, {"http", 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TopTags(3) = %v", got)
+	}
+	if got := r.TopTags(10); len(got) != 4 || got[3].Value != "api" {
+		t.Errorf("TopTags(10) = %v", got)
+	}
+	if got := r.TopTags(0); len(got) != 4 {
+		t.Errorf("TopTags(0) = %v", got)
+	}
+	// The response's own facets are left in server order.
+	if r.Facets[FacetTag][0].Value != "http" {
+		t.Errorf("facets reordered: %v", r.Facets[FacetTag])
+	}
+	if got := (&QueryResponse{}).TopTags(3); len(got) != 0 {
+		t.Errorf("no facets: %v", got)
+	}
+}
@@ -0,0 +1,145 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimelineEvent is one entry in an incident's narrative.
+type TimelineEvent struct {
+	At   time.Time `json:"at"`
+	Note string    `json:"note"`
+}
+
+// FailureFilter narrows ListFailures. Zero fields are not sent.
+type FailureFilter struct {
+	Pattern       Pattern
+	Severity      Severity
+	Tags          []string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+
+	page
+}
+
+// Validate reports an inverted created range.
+func (f FailureFilter) Validate() error {
+	v := newValidator("FailureFilter")
+	v.timeRange("created_after", "created_before", f.CreatedAfter, f.CreatedBefore)
+	return v.err()
+}
+
+func (f FailureFilter) values() url.Values {
+	q := url.Values{}
+	if f.Pattern != "" {
+		q.Set("pattern", string(f.Pattern))
+	}
+	if f.Severity != "" {
+		q.Set("severity", string(f.Severity))
+	}
+	if len(f.Tags) > 0 {
+		q.Set("tags", strings.Join(f.Tags, ","))
+	}
+	setTimeRange(q, f.CreatedAfter, f.CreatedBefore)
+	f.page.set(q)
+	return q
+}
+
+// page is a limit/offset window on a list call, for ExportBundle. The zero
+// value lists everything.
+type page struct {
+	limit, offset int
+}
+
+func (p page) set(q url.Values) {
+	if p.limit > 0 {
+		q.Set("limit", strconv.Itoa(p.limit))
+		q.Set("offset", strconv.Itoa(p.offset))
+	}
+}
+
+// setTimeRange adds the non-zero ends of a created range to q.
+func setTimeRange(q url.Values, after, before time.Time) {
+	if !after.IsZero() {
+		q.Set("created_after", after.UTC().Format(time.RFC3339))
+	}
+	if !before.IsZero() {
+		q.Set("created_before", before.UTC().Format(time.RFC3339))
+	}
+}
+
+func (c *Client) ListFailures(ctx context.Context, filter FailureFilter, opts ...CallOption) ([]Issue, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	filter.Tags = c.tags(filter.Tags)
+	var issues []Issue
+	err := c.do(ctx, call{
+		op:     "ListFailures",
+		method: http.MethodGet,
+		path:   "/failure",
+		query:  filter.values(),
+		out:    &issues,
+		opts:   opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return issues, nil
+}
+
+func (c *Client) GetFailure(ctx context.Context, id string, opts ...CallOption) (*Issue, error) {
+	var resp struct {
+		Failure Issue `json:"failure"`
+	}
+	err := c.do(ctx, call{
+		op:     "GetFailure",
+		method: http.MethodGet,
+		path:   "/failure/" + url.PathEscape(id),
+		out:    &resp,
+		opts:   opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(resp.Failure.Timeline, func(i, j int) bool {
+		return resp.Failure.Timeline[i].At.Before(resp.Failure.Timeline[j].At)
+	})
+	return &resp.Failure, nil
+}
+
+// AppendFailureEvent adds event to the timeline of failure id. A zero At is
+// stamped with the current time.
+func (c *Client) AppendFailureEvent(ctx context.Context, id string, event TimelineEvent, opts ...CallOption) error {
+	if event.At.IsZero() {
+		event.At = time.Now().UTC()
+	}
+	return c.do(ctx, call{
+		op:     "AppendFailureEvent",
+		method: http.MethodPost,
+		path:   "/failure/" + url.PathEscape(id) + "/timeline",
+		in:     event,
+		opts:   opts,
+		keyed:  true,
+	})
+}
+
+// LinkFailureToADR records that decision adrID is related to failure
+// failureID. Linking the same pair twice is a no-op on the server.
+func (c *Client) LinkFailureToADR(ctx context.Context, failureID, adrID string, opts ...CallOption) error {
+	return c.do(ctx, call{
+		op:     "LinkFailureToADR",
+		method: http.MethodPost,
+		path:   "/failure/" + url.PathEscape(failureID) + "/adrs",
+		in:     map[string]string{"adr_id": adrID},
+		opts:   opts,
+		keyed:  true,
+	})
+}
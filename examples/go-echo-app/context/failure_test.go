@@ -0,0 +1,91 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFailureTimeline(t *testing.T) {
+	var appended map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/failure/f 1/timeline":
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &appended)
+		case r.Method == http.MethodGet && r.URL.Path == "/failure/f 1":
+			w.Write([]byte(`{"failure":{"id":"f 1","timeline":[
+				{"at":"2026-03-01T12:10:00Z","note":"resolved"},
+				{"at":"2026-03-01T12:00:00Z","note":"paged"},
+				{"at":"2026-03-01T12:05:00Z","note":"rolled back"}]}}`))
+		default:
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := c.AppendFailureEvent(context.Background(), "f 1", TimelineEvent{At: at, Note: "paged"}); err != nil {
+		t.Fatal(err)
+	}
+	if appended["at"] != "2026-03-01T12:00:00Z" || appended["note"] != "paged" || len(appended) != 2 {
+		t.Errorf("appended %v", appended)
+	}
+	before := time.Now()
+	if err := c.AppendFailureEvent(context.Background(), "f 1", TimelineEvent{Note: "now"}); err != nil {
+		t.Fatal(err)
+	}
+	if stamped, err := time.Parse(time.RFC3339Nano, appended["at"].(string)); err != nil || stamped.Before(before.Add(-time.Second)) {
+		t.Errorf("zero At stamped as %v (%v)", appended["at"], err)
+	}
+
+	f, err := c.GetFailure(context.Background(), "f 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notes []string
+	for _, e := range f.Timeline {
+		notes = append(notes, e.Note)
+	}
+	if len(notes) != 3 || notes[0] != "paged" || notes[1] != "rolled back" || notes[2] != "resolved" {
+		t.Errorf("timeline = %v, want oldest first", notes)
+	}
+}
+
+func TestUpdateRunbook(t *testing.T) {
+	var method, path, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(b)
+	}))
+	defer srv.Close()
+
+	if err := NewClient(srv.URL).UpdateRunbook(context.Background(), "f-1", "https://runbooks/pool"); err != nil {
+		t.Fatal(err)
+	}
+	// Only the runbook is sent, so other fields are left alone.
+	if method != http.MethodPatch || path != "/failure/f-1" || body != `{"runbook":"https://runbooks/pool"}` {
+		t.Errorf("%s %s %s", method, path, body)
+	}
+}
+
+func TestLinkFailureToADR(t *testing.T) {
+	var method, path, body, key string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, path, body, key = r.Method, r.URL.EscapedPath(), string(b), r.Header.Get("Idempotency-Key")
+	}))
+	defer srv.Close()
+
+	if err := NewClient(srv.URL).LinkFailureToADR(context.Background(), "f/1", "adr-7"); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPost || path != "/failure/f%2F1/adrs" || body != `{"adr_id":"adr-7"}` || key == "" {
+		t.Errorf("%s %s %s key %q", method, path, body, key)
+	}
+}
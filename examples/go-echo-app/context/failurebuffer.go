@@ -0,0 +1,188 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RecordFailures records reqs in one request to /failure/bulk and returns
+// their IDs in input order. Invalid records and records the server rejects
+// are reported exactly as by CreateADRs.
+func (c *Client) RecordFailures(ctx context.Context, reqs []FailureRequest, opts ...CallOption) ([]string, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	continueOnError := resolveCallOptions(opts).continueOnError
+
+	ids := make([]string, len(reqs))
+	errs := map[int]error{}
+	var (
+		failures []FailureRequest
+		sent     []int // index in reqs of each entry in failures
+	)
+	for i, req := range reqs {
+		if err := req.Validate(); err != nil {
+			errs[i] = err
+			continue
+		}
+		req.Tags = c.tags(req.Tags)
+		failures = append(failures, req)
+		sent = append(sent, i)
+	}
+	if len(failures) == 0 || (len(errs) > 0 && !continueOnError) {
+		return ids, batchErr(errs)
+	}
+
+	var resp struct {
+		IDs    []string       `json:"ids"`
+		Errors map[int]string `json:"errors"`
+	}
+	err := c.do(ctx, call{
+		op:     "RecordFailures",
+		method: http.MethodPost,
+		path:   "/failure/bulk",
+		in: map[string]any{
+			"failures":          failures,
+			"continue_on_error": continueOnError,
+		},
+		out:   &resp,
+		opts:  opts,
+		keyed: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for j, id := range resp.IDs {
+		if j < len(sent) {
+			ids[sent[j]] = id
+		}
+	}
+	for j, msg := range resp.Errors {
+		if j >= 0 && j < len(sent) {
+			errs[sent[j]] = errors.New(msg)
+		}
+	}
+	return ids, batchErr(errs)
+}
+
+// WithFailureBuffer makes RecordFailure buffer its records and send them
+// through RecordFailures, once size have accumulated or every
+// flushInterval, whichever comes first. Buffered calls return as soon as
+// the record is validated, and their call options are ignored. A batch the
+// server can't take is moved to the write queue when WithWriteQueue is
+// set, and otherwise dropped. Close sends whatever is still buffered.
+func WithFailureBuffer(size int, flushInterval time.Duration) Option {
+	return func(c *Client) {
+		c.failures = &failureBuffer{
+			size:     max(size, 1),
+			interval: flushInterval,
+			full:     make(chan struct{}, 1),
+		}
+	}
+}
+
+type failureBuffer struct {
+	size     int
+	interval time.Duration
+	full     chan struct{} // signals the flush loop that size was reached
+
+	mu      sync.Mutex
+	pending []FailureRequest
+}
+
+func (b *failureBuffer) add(req FailureRequest) {
+	b.mu.Lock()
+	b.pending = append(b.pending, req)
+	full := len(b.pending) >= b.size
+	b.mu.Unlock()
+	if full {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// take removes and returns up to n buffered records, oldest first.
+func (b *failureBuffer) take(n int) []FailureRequest {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n = min(n, len(b.pending))
+	reqs := append([]FailureRequest(nil), b.pending[:n]...)
+	b.pending = b.pending[n:]
+	if len(b.pending) >= b.size {
+		// Still full: have the loop come round again.
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+	return reqs
+}
+
+func (c *Client) failureFlushLoop() {
+	defer c.wg.Done()
+	var tick <-chan time.Time
+	if c.failures.interval > 0 {
+		t := time.NewTicker(c.failures.interval)
+		defer t.Stop()
+		tick = t.C
+	}
+	for {
+		all := false
+		select {
+		case <-c.done:
+			return
+		case <-tick:
+			all = true
+		case <-c.failures.full:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+		if all {
+			_ = c.flushAllFailures(ctx)
+		} else {
+			_ = c.flushFailures(ctx, c.failures.take(c.failures.size))
+		}
+		cancel()
+	}
+}
+
+// flushAllFailures sends everything buffered, in batches of at most size.
+func (c *Client) flushAllFailures(ctx context.Context) error {
+	var errs []error
+	for {
+		reqs := c.failures.take(c.failures.size)
+		if len(reqs) == 0 {
+			return errors.Join(errs...)
+		}
+		if err := c.flushFailures(ctx, reqs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+}
+
+// flushFailures sends reqs, queueing them for replay if the server is
+// unavailable and a write queue is configured.
+func (c *Client) flushFailures(ctx context.Context, reqs []FailureRequest) error {
+	if len(reqs) == 0 {
+		return nil
+	}
+	_, err := c.RecordFailures(ctx, reqs, WithContinueOnError())
+	var batch *BatchError
+	if err == nil || errors.As(err, &batch) || c.queue == nil || !queueable(err) {
+		return err
+	}
+
+	errs := []error{err}
+	for _, req := range reqs {
+		cl := call{op: "RecordFailure", method: http.MethodPost, path: "/failure", in: req}
+		if qerr := c.queue.save(cl, c.idempotencyKey()); qerr != nil {
+			errs = append(errs, qerr)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFailureBuffer(t *testing.T) {
+	batches := make(chan int, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/failure/bulk" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var body struct {
+			Failures []FailureRequest `json:"failures"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		ids := make([]string, len(body.Failures))
+		for i := range ids {
+			ids[i] = fmt.Sprintf("F-%d", i)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"ids": ids})
+		batches <- len(body.Failures)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithFailureBuffer(3, time.Hour))
+	fail := FailureRequest{Title: "t", RootCause: "r", Severity: SeverityHigh}
+	for i := 0; i < 4; i++ {
+		if err := c.RecordFailure(context.Background(), fail); err != nil {
+			t.Fatal(err)
+		}
+	}
+	next := func() int {
+		select {
+		case n := <-batches:
+			return n
+		case <-time.After(2 * time.Second):
+			t.Fatal("no batch sent")
+			return 0
+		}
+	}
+	if n := next(); n != 3 {
+		t.Errorf("first batch = %d records", n)
+	}
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := next(); n != 1 {
+		t.Errorf("batch on close = %d records", n)
+	}
+
+	ids, err := NewClient(srv.URL).RecordFailures(context.Background(), []FailureRequest{fail, fail})
+	if err != nil || len(ids) != 2 || ids[1] != "F-1" {
+		t.Errorf("RecordFailures = %v, %v", ids, err)
+	}
+}
+
+func TestFailureBufferAfterClose(t *testing.T) {
+	c := NewClient("http://127.0.0.1:1", WithFailureBuffer(10, time.Hour))
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	err := c.RecordFailure(context.Background(), FailureRequest{Title: "t", RootCause: "r", Severity: SeverityHigh})
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("err = %v", err)
+	}
+}
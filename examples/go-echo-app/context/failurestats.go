@@ -0,0 +1,91 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StatsBucket is the width of each point in FailureStats.Series.
+type StatsBucket string
+
+const (
+	BucketDay  StatsBucket = "day"
+	BucketWeek StatsBucket = "week"
+)
+
+// StatsOptions narrows FailureStats. Zero fields are not sent; the server
+// buckets by day unless Bucket says otherwise.
+type StatsOptions struct {
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Tags          []string
+	Bucket        StatsBucket
+}
+
+// Validate reports an inverted created range or an unknown bucket.
+func (o StatsOptions) Validate() error {
+	v := newValidator("StatsOptions")
+	v.timeRange("created_after", "created_before", o.CreatedAfter, o.CreatedBefore)
+	switch o.Bucket {
+	case "", BucketDay, BucketWeek:
+	default:
+		v.add("bucket", "must be day or week")
+	}
+	return v.err()
+}
+
+func (o StatsOptions) values() url.Values {
+	q := url.Values{}
+	if len(o.Tags) > 0 {
+		q.Set("tags", strings.Join(o.Tags, ","))
+	}
+	if o.Bucket != "" {
+		q.Set("bucket", string(o.Bucket))
+	}
+	setTimeRange(q, o.CreatedAfter, o.CreatedBefore)
+	return q
+}
+
+// FailureStats counts the failures matching a StatsOptions.
+type FailureStats struct {
+	Total      int              `json:"total"`
+	ByPattern  map[Pattern]int  `json:"by_pattern"`
+	BySeverity map[Severity]int `json:"by_severity"`
+	// Series is oldest first, one point per bucket.
+	Series []StatsPoint `json:"series"`
+}
+
+// StatsPoint is the number of failures recorded in the bucket starting at
+// Start.
+type StatsPoint struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// FailureStats returns failure counts by pattern, by severity and over time,
+// aggregated by the server from /failure/stats.
+func (c *Client) FailureStats(ctx context.Context, opts StatsOptions, callOpts ...CallOption) (FailureStats, error) {
+	if err := opts.Validate(); err != nil {
+		return FailureStats{}, err
+	}
+	opts.Tags = c.tags(opts.Tags)
+	var stats FailureStats
+	err := c.do(ctx, call{
+		op:     "FailureStats",
+		method: http.MethodGet,
+		path:   "/failure/stats",
+		query:  opts.values(),
+		out:    &stats,
+		opts:   callOpts,
+	})
+	if err != nil {
+		return FailureStats{}, err
+	}
+
+	sort.Slice(stats.Series, func(i, j int) bool { return stats.Series[i].Start.Before(stats.Series[j].Start) })
+	return stats, nil
+}
@@ -0,0 +1,48 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestFailureStats(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/failure/stats" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		query = r.URL.Query()
+		w.Write([]byte(`{"total":3,"by_pattern":{"timeout":2,"validation":1},"by_severity":{"high":3},
+			"series":[{"start":"2026-01-08T00:00:00Z","count":1},{"start":"2026-01-01T00:00:00Z","count":2}]}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	stats, err := c.FailureStats(context.Background(), StatsOptions{
+		CreatedAfter: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Tags:         []string{"db", "db"},
+		Bucket:       BucketWeek,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("bucket") != "week" || query.Get("tags") != "db" || query.Get("created_after") != "2026-01-01T00:00:00Z" {
+		t.Errorf("query = %v", query)
+	}
+	if stats.Total != 3 || stats.ByPattern[PatternTimeout] != 2 || stats.BySeverity[SeverityHigh] != 3 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if len(stats.Series) != 2 || stats.Series[0].Count != 2 {
+		t.Errorf("series not oldest first: %+v", stats.Series)
+	}
+
+	var verr *ValidationError
+	if _, err := c.FailureStats(context.Background(), StatsOptions{Bucket: "month"}); !errors.As(err, &verr) {
+		t.Errorf("bucket month: err = %v", err)
+	}
+}
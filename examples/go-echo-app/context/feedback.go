@@ -0,0 +1,49 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Feedback tells the server how useful a decision was, so it can rank
+// better next time.
+type Feedback struct {
+	Helpful bool `json:"helpful"`
+	// Score is an optional rating from 1 to 5; 0 leaves it unset.
+	Score   int    `json:"score,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// QueryID ties the feedback to the query that surfaced the decision;
+	// see QueryResponse.QueryID.
+	QueryID string `json:"query_id,omitempty"`
+}
+
+// Validate checks that Score, if set, is between 1 and 5.
+func (f Feedback) Validate() error {
+	v := newValidator("Feedback")
+	if f.Score < 0 || f.Score > 5 {
+		v.add("score", fmt.Sprintf("must be between 1 and 5, got %d", f.Score))
+	}
+	return v.err()
+}
+
+// SendFeedback records fb against the decision id.
+func (c *Client) SendFeedback(ctx context.Context, id string, fb Feedback, opts ...CallOption) error {
+	if err := fb.Validate(); err != nil {
+		return err
+	}
+	return c.do(ctx, call{
+		op:     "SendFeedback",
+		method: http.MethodPost,
+		path:   "/adr/" + url.PathEscape(id) + "/feedback",
+		in:     fb,
+		opts:   opts,
+		keyed:  true,
+	})
+}
+
+// RateDecision is shorthand for SendFeedback with only Helpful set.
+func (c *Client) RateDecision(ctx context.Context, id string, helpful bool, opts ...CallOption) error {
+	return c.SendFeedback(ctx, id, Feedback{Helpful: helpful}, opts...)
+}
@@ -0,0 +1,42 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRateDecision(t *testing.T) {
+	var got []Feedback
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/adr/adr%2F1/feedback" {
+			t.Errorf("path = %s", r.URL.EscapedPath())
+		}
+		var fb Feedback
+		if err := json.NewDecoder(r.Body).Decode(&fb); err != nil {
+			t.Error(err)
+		}
+		got = append(got, fb)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	if err := c.RateDecision(context.Background(), "adr/1", true); err != nil {
+		t.Fatal(err)
+	}
+	fb := Feedback{Score: 2, Comment: "outdated", QueryID: "q-9"}
+	if err := c.SendFeedback(context.Background(), "adr/1", fb); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || !got[0].Helpful || got[1] != fb {
+		t.Errorf("feedback = %+v", got)
+	}
+
+	var verr *ValidationError
+	if err := c.SendFeedback(context.Background(), "adr/1", Feedback{Score: 6}); !errors.As(err, &verr) || len(got) != 2 {
+		t.Errorf("score 6: err = %v", err)
+	}
+}
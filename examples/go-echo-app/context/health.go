@@ -0,0 +1,28 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// pingTimeout bounds Ping regardless of the client timeout so a slow backend
+// can't stall startup or readiness probes.
+const pingTimeout = 2 * time.Second
+
+// Ping checks that the context engine is reachable. It returns nil on 200.
+func (c *Client) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	err := c.do(ctx, call{
+		op:     "Ping",
+		method: http.MethodGet,
+		path:   "/health",
+	})
+	if err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPing(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	if err := c.Ping(context.Background()); err != nil {
+		t.Errorf("reachable: %v", err)
+	}
+
+	status = http.StatusServiceUnavailable
+	var apiErr *APIError
+	if err := c.Ping(context.Background()); !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+		t.Errorf("5xx: err = %v", err)
+	}
+
+	refused := httptest.NewServer(http.NotFoundHandler())
+	url := refused.URL
+	refused.Close()
+	if err := NewClient(url).Ping(context.Background()); err == nil || errors.As(err, &apiErr) {
+		t.Errorf("refused: err = %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package context
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WithHedging sends a second copy of a read that hasn't responded within
+// delay and uses whichever response arrives first, cancelling the other.
+// It applies to reads only (Query, Search and GET requests such as the Get
+// and List methods), never to writes, and makes at most two attempts per
+// request. delay <= 0 disables it.
+func WithHedging(delay time.Duration) Option {
+	return func(c *Client) {
+		c.hedgeDelay = delay
+	}
+}
+
+func (c *Client) hedgeable(cl call) bool {
+	return c.hedgeDelay > 0 && (cl.read || cl.method == http.MethodGet || cl.method == http.MethodHead)
+}
+
+// roundTrip sends req, hedging it if hedge is set.
+func (c *Client) roundTrip(req *http.Request, hedge bool) (*http.Response, error) {
+	if !hedge {
+		return c.client.Do(req)
+	}
+
+	type result struct {
+		n    int
+		resp *http.Response
+		err  error
+	}
+	results := make(chan result, 2)
+	var cancels []context.CancelFunc
+	launch := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		r := req.Clone(ctx)
+		if req.GetBody != nil {
+			r.Body, _ = req.GetBody()
+		}
+		n := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := c.client.Do(r)
+			results <- result{n, resp, err}
+		}()
+	}
+
+	launch()
+	timer := time.NewTimer(c.hedgeDelay)
+	defer timer.Stop()
+
+	for pending := 1; ; {
+		select {
+		case <-timer.C:
+			if len(cancels) == 1 {
+				launch()
+				pending++
+			}
+		case res := <-results:
+			pending--
+			lost := res.err != nil || res.resp.StatusCode >= 500
+			if lost && pending > 0 {
+				// The other attempt may still succeed.
+				if res.resp != nil {
+					closeBody(res.resp)
+				}
+				cancels[res.n]()
+				continue
+			}
+
+			// Cancel the loser and discard whatever it returns.
+			for n, cancel := range cancels {
+				if n != res.n {
+					cancel()
+				}
+			}
+			if pending > 0 {
+				go func() {
+					if r := <-results; r.resp != nil {
+						closeBody(r.resp)
+					}
+				}()
+			}
+			if res.err != nil {
+				cancels[res.n]()
+				return nil, res.err
+			}
+			res.resp.Body = cancelOnClose{res.resp.Body, cancels[res.n]}
+			return res.resp, nil
+		}
+	}
+}
+
+func closeBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body.Close()
+}
+
+// cancelOnClose releases a hedged attempt's context once its body is done.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
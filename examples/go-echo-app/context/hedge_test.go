@@ -0,0 +1,53 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedging(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// The first attempt stalls until the hedge has answered.
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		json.NewEncoder(w).Encode(QueryResponse{TotalItems: 2})
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := NewClient(srv.URL, WithHedging(10*time.Millisecond))
+	resp, err := c.Query(context.Background(), QueryRequest{Query: "q"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.TotalItems != 2 || calls.Load() != 2 {
+		t.Errorf("total = %d, calls = %d", resp.TotalItems, calls.Load())
+	}
+
+	// Writes are never hedged, however slow.
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer slow.Close()
+	calls.Store(0)
+	w := NewClient(slow.URL, WithHedging(10*time.Millisecond))
+	if err := w.CreateADR(context.Background(), ADRRequest{Title: "t", Context: "c", Decision: "d"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("write sent %d times", n)
+	}
+}
@@ -0,0 +1,71 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SupersedeADR marks ADR oldID as replaced by newID. The server records the
+// link on both: Supersedes on the new ADR and SupersededBy on the old.
+func (c *Client) SupersedeADR(ctx context.Context, oldID, newID string, opts ...CallOption) error {
+	return c.do(ctx, call{
+		op:     "SupersedeADR",
+		method: http.MethodPost,
+		path:   "/adr/" + url.PathEscape(oldID) + "/supersede",
+		in:     map[string]string{"superseded_by": newID},
+		opts:   opts,
+		keyed:  true,
+	})
+}
+
+// CycleError reports a supersession chain that loops back on itself.
+type CycleError struct {
+	ID string // the ADR reached twice
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("ADR supersession cycle at %s", e.ID)
+}
+
+// ADRHistory returns the supersession chain containing id, oldest first:
+// it follows Supersedes back to the original decision and SupersededBy
+// forward to the one currently in force. A chain that revisits an ADR
+// returns a *CycleError.
+func (c *Client) ADRHistory(ctx context.Context, id string, opts ...CallOption) ([]Decision, error) {
+	start, err := c.GetADR(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	}
+	visited := map[string]bool{start.ID: true}
+	visit := func(id string) (*Decision, error) {
+		if visited[id] {
+			return nil, &CycleError{ID: id}
+		}
+		visited[id] = true
+		return c.GetADR(ctx, id, opts...)
+	}
+
+	var older []Decision
+	for d := start; d.Supersedes != ""; {
+		if d, err = visit(d.Supersedes); err != nil {
+			return nil, err
+		}
+		older = append(older, *d)
+	}
+
+	chain := make([]Decision, 0, len(older)+1)
+	for i := len(older) - 1; i >= 0; i-- {
+		chain = append(chain, older[i])
+	}
+	chain = append(chain, *start)
+
+	for d := start; d.SupersededBy != ""; {
+		if d, err = visit(d.SupersededBy); err != nil {
+			return nil, err
+		}
+		chain = append(chain, *d)
+	}
+	return chain, nil
+}
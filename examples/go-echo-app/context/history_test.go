@@ -0,0 +1,42 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestADRHistory(t *testing.T) {
+	links := map[string][2]string{ // id: {supersedes, superseded_by}
+		"a": {"", "b"}, "b": {"a", "c"}, "c": {"b", ""},
+		"x": {"", "y"}, "y": {"", "x"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/adr/")
+		l := links[id]
+		_ = json.NewEncoder(w).Encode(map[string]Decision{"adr": {ID: id, Supersedes: l[0], SupersededBy: l[1]}})
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	chain, err := c.ADRHistory(context.Background(), "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, d := range chain {
+		ids = append(ids, d.ID)
+	}
+	if got := strings.Join(ids, ","); got != "a,b,c" {
+		t.Errorf("chain = %s", got)
+	}
+
+	var cycle *CycleError
+	if _, err := c.ADRHistory(context.Background(), "x"); !errors.As(err, &cycle) || cycle.ID != "x" {
+		t.Errorf("err = %v, want cycle at x", err)
+	}
+}
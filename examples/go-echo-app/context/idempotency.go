@@ -0,0 +1,33 @@
+package context
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// WithIdempotencyKeyFunc replaces the UUIDv4 generator used for the
+// Idempotency-Key header on CreateADR and RecordFailure. The key is generated
+// once per logical call and reused across retry attempts.
+func WithIdempotencyKeyFunc(fn func() string) Option {
+	return func(c *Client) {
+		c.idempotencyKey = fn
+	}
+}
+
+// WithIdempotencyKey sends key instead of a generated one, e.g. to tie a
+// write to an upstream event ID.
+func WithIdempotencyKey(key string) CallOption {
+	return func(o *callOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("context: read random bytes: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
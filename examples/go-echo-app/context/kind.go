@@ -0,0 +1,10 @@
+package context
+
+// Kind names one of the entity types the context engine stores.
+type Kind string
+
+const (
+	KindDecision Kind = "decision"
+	KindIssue    Kind = "issue"
+	KindChange   Kind = "change"
+)
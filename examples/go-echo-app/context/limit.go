@@ -0,0 +1,43 @@
+package context
+
+import (
+	"errors"
+	"io"
+)
+
+const defaultMaxResponseBytes = 10 << 20
+
+// ErrResponseTooLarge is returned, wrapped, when a response body exceeds
+// the WithMaxResponseBytes limit.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// WithMaxResponseBytes caps how much of a response body is read, counted
+// after gzip decoding. The default is 10 MiB; n <= 0 removes the cap.
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
+
+// limitReader is io.LimitReader that fails with ErrResponseTooLarge instead
+// of reporting a silent EOF at the limit.
+type limitReader struct {
+	r io.Reader
+	n int64 // bytes left before the limit
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// Allow one byte past the limit so a body of exactly n bytes succeeds.
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, ErrResponseTooLarge
+	}
+	return n, err
+}
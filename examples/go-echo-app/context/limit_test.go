@@ -0,0 +1,26 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseBytes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"key_decisions":[{"id":%q}]}`, strings.Repeat("x", 1000))
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL, WithMaxResponseBytes(100)).Query(context.Background(), QueryRequest{Query: "q"})
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("err = %v, want ErrResponseTooLarge", err)
+	}
+	if _, err := NewClient(srv.URL).Query(context.Background(), QueryRequest{Query: "q"}); err != nil {
+		t.Errorf("default limit err = %v", err)
+	}
+}
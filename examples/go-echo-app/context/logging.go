@@ -0,0 +1,91 @@
+package context
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestInfo describes one completed request. It is passed to the hook
+// installed with WithLogger after every call, successful or not.
+type RequestInfo struct {
+	Method     string // client method, e.g. "CreateADR"
+	HTTPMethod string
+	URL        string
+	StatusCode int // 0 when no response was received
+	Duration   time.Duration
+	Header     http.Header // request headers with credentials redacted
+	// RequestID is the X-Request-ID the server echoed, or else the one sent.
+	RequestID string
+	Err       error
+	// Warnings are non-fatal problems with the call, e.g. a query naming a
+	// domain the server doesn't know.
+	Warnings []string
+}
+
+// WithLogger installs fn as a request/response hook. fn runs synchronously
+// on the calling goroutine, so it should not block.
+func WithLogger(fn func(RequestInfo)) Option {
+	return func(c *Client) {
+		c.logger = fn
+	}
+}
+
+// WithSlowRequestLog calls fn only for requests that take longer than
+// threshold. It can be combined with WithLogger.
+func WithSlowRequestLog(threshold time.Duration, fn func(RequestInfo)) Option {
+	return func(c *Client) {
+		c.slowThreshold = threshold
+		c.slowLogger = fn
+	}
+}
+
+const redacted = "[REDACTED]"
+
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if _, ok := out[name]; ok {
+			out[name] = []string{redacted}
+		}
+	}
+	return out
+}
+
+func (c *Client) observe(cl call, req *http.Request, status int, d time.Duration, err error) {
+	c.stats.observe(status, d, err)
+	if c.metrics != nil {
+		c.metrics.ObserveRequest(cl.op, status, err, d)
+	}
+	slow := c.slowLogger != nil && d > c.slowThreshold
+	if c.logger == nil && !slow {
+		return
+	}
+
+	info := RequestInfo{
+		Method:     cl.op,
+		HTTPMethod: cl.method,
+		StatusCode: status,
+		Duration:   d,
+		RequestID:  cl.requestID,
+		Err:        err,
+		Warnings:   cl.warnings,
+	}
+	if req != nil {
+		info.URL = c.redact(req.URL.String())
+		info.Header = redactHeader(req.Header)
+	}
+	if c.logger != nil {
+		c.logger(info)
+	}
+	if slow {
+		c.slowLogger(info)
+	}
+}
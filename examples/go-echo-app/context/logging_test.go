@@ -0,0 +1,68 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerRedactsCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			t.Errorf("Authorization not sent to server")
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	var infos []RequestInfo
+	c := NewClient(srv.URL,
+		WithHeader("Authorization", "Bearer s3cret"),
+		WithHeader("X-API-Key", "k3y"),
+		WithLogger(func(info RequestInfo) { infos = append(infos, info) }),
+	)
+	if err := c.CreateADR(context.Background(), ADRRequest{Title: "t", Decision: "d"}); err != nil {
+		t.Fatalf("CreateADR: %v", err)
+	}
+
+	if len(infos) != 1 {
+		t.Fatalf("logger called %d times, want 1", len(infos))
+	}
+	info := infos[0]
+	if info.Method != "CreateADR" || info.StatusCode != http.StatusCreated || info.URL != srv.URL+"/adr" {
+		t.Errorf("info = %+v", info)
+	}
+	for _, name := range []string{"Authorization", "X-Api-Key"} {
+		if got := info.Header.Get(name); got != redacted {
+			t.Errorf("%s = %q, want redacted", name, got)
+		}
+	}
+}
+
+func TestSlowRequestLog(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(60 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	var slow, all []string
+	c := NewClient(srv.URL,
+		WithSlowRequestLog(30*time.Millisecond, func(ri RequestInfo) { slow = append(slow, ri.URL) }),
+		WithLogger(func(ri RequestInfo) { all = append(all, ri.URL) }))
+	for _, path := range []string{"/fast", "/slow", "/fast"} {
+		if err := c.Do(context.Background(), http.MethodGet, path, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(slow) != 1 || !strings.HasSuffix(slow[0], "/slow") {
+		t.Errorf("slow hook got %q", slow)
+	}
+	if len(all) != 3 {
+		t.Errorf("logger got %d requests", len(all))
+	}
+}
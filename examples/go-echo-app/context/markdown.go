@@ -0,0 +1,59 @@
+package context
+
+import "strings"
+
+// Markdown renders d as an ADR document: the title as H1, then Status,
+// Context, Decision, Options Considered, Tags and Stakeholders. Empty
+// sections are left out. Options keep their order if the server sent
+// OptionsConsideredOrdered and are otherwise sorted by name, so the output
+// is stable for diffing.
+func (d Decision) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# " + d.Title + "\n")
+
+	section := func(heading, body string) {
+		if body = strings.TrimSpace(body); body != "" {
+			b.WriteString("\n## " + heading + "\n\n" + body + "\n")
+		}
+	}
+	bullets := func(items []string, indent string) string {
+		var s strings.Builder
+		for _, it := range items {
+			s.WriteString(indent + "- " + it + "\n")
+		}
+		return s.String()
+	}
+
+	section("Status", d.Status)
+	section("Context", d.Context)
+	section("Decision", d.Decision)
+
+	var opts strings.Builder
+	for _, o := range orderedOptions(d.OptionsConsidered, d.OptionsConsideredOrdered) {
+		opts.WriteString("- **" + o.Name + "**\n")
+		opts.WriteString(bullets(o.Pros, "  "))
+	}
+	section("Options Considered", opts.String())
+
+	tags := make([]string, len(d.Tags))
+	for i, t := range d.Tags {
+		tags[i] = "`" + t + "`"
+	}
+	section("Tags", strings.Join(tags, ", "))
+	section("Stakeholders", bullets(d.Stakeholders, ""))
+	return b.String()
+}
+
+// Markdown renders r as Decision.Markdown would render the ADR it creates,
+// with OptionsConsideredOrdered in the order given.
+func (r ADRRequest) Markdown() string {
+	return Decision{
+		Title:                    r.Title,
+		Context:                  r.Context,
+		Decision:                 r.Decision,
+		OptionsConsidered:        r.OptionsConsidered,
+		OptionsConsideredOrdered: r.OptionsConsideredOrdered,
+		Tags:                     r.Tags,
+		Stakeholders:             r.Stakeholders,
+	}.Markdown()
+}
@@ -0,0 +1,158 @@
+package context
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MarkdownError points at the part of an ADR document ParseADRMarkdown could
+// not understand.
+type MarkdownError struct {
+	Line    int
+	Section string // lower-cased heading, or "" before the first one
+	Problem string
+}
+
+func (e *MarkdownError) Error() string {
+	if e.Section == "" {
+		return fmt.Sprintf("parse ADR markdown: line %d: %s", e.Line, e.Problem)
+	}
+	return fmt.Sprintf("parse ADR markdown: line %d in %q: %s", e.Line, e.Section, e.Problem)
+}
+
+// ParseADRMarkdown reads an ADR in the template Decision.Markdown produces.
+// Heading levels, case, trailing colons, "*" or "-" bullets and bold option
+// names are all accepted; unknown sections are ignored. The result is
+// checked with ADRRequest.Validate.
+func ParseADRMarkdown(r io.Reader) (ADRRequest, error) {
+	var req ADRRequest
+	var section, option string
+	var text []string
+
+	flush := func() {
+		body := strings.TrimSpace(strings.Join(text, "\n"))
+		switch section {
+		case "context":
+			req.Context = body
+		case "decision":
+			req.Decision = body
+		}
+		text = nil
+	}
+
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimRight(sc.Text(), " \t")
+		fail := func(problem string) (ADRRequest, error) {
+			return ADRRequest{}, &MarkdownError{Line: n, Section: section, Problem: problem}
+		}
+
+		if level, heading, ok := parseHeading(line); ok {
+			if level == 1 && req.Title == "" {
+				req.Title = heading
+				continue
+			}
+			flush()
+			section, option = normalizeSection(heading), ""
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			if section == "context" || section == "decision" {
+				text = append(text, "")
+			}
+			continue
+		}
+		if req.Title == "" {
+			return fail("expected a # title before any content")
+		}
+
+		indent, item, isItem := parseBullet(line)
+		switch section {
+		case "context", "decision":
+			text = append(text, line)
+		case "options considered":
+			if !isItem {
+				return fail("expected a list of options")
+			}
+			if indent == 0 {
+				option = strings.Trim(item, "*_ ")
+				if req.OptionsConsidered == nil {
+					req.OptionsConsidered = make(map[string][]string)
+				}
+				if _, dup := req.OptionsConsidered[option]; dup {
+					return fail(fmt.Sprintf("option %q listed twice", option))
+				}
+				req.OptionsConsidered[option] = nil
+				continue
+			}
+			if option == "" {
+				return fail("nested item before any option")
+			}
+			req.OptionsConsidered[option] = append(req.OptionsConsidered[option], item)
+		case "tags":
+			if isItem {
+				line = item
+			}
+			for _, tag := range strings.Split(line, ",") {
+				if tag = strings.Trim(tag, "` \t"); tag != "" {
+					req.Tags = append(req.Tags, tag)
+				}
+			}
+		case "stakeholders":
+			if isItem {
+				req.Stakeholders = append(req.Stakeholders, item)
+				continue
+			}
+			for _, s := range strings.Split(line, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					req.Stakeholders = append(req.Stakeholders, s)
+				}
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return ADRRequest{}, fmt.Errorf("parse ADR markdown: %w", err)
+	}
+	flush()
+
+	if err := req.Validate(); err != nil {
+		return ADRRequest{}, err
+	}
+	return req, nil
+}
+
+func parseHeading(line string) (level int, text string, ok bool) {
+	level = len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || (len(line) > level && line[level] != ' ') {
+		return 0, "", false
+	}
+	return level, strings.TrimSpace(line[level:]), true
+}
+
+func normalizeSection(heading string) string {
+	s := strings.ToLower(strings.TrimRight(heading, ": "))
+	switch s {
+	case "options", "considered options":
+		return "options considered"
+	}
+	return s
+}
+
+// parseBullet reports whether line is a "-", "*" or "+" list item, with its
+// indentation in spaces (tabs count as two).
+func parseBullet(line string) (indent int, item string, ok bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	for _, r := range line[:len(line)-len(trimmed)] {
+		if r == '\t' {
+			indent += 2
+		} else {
+			indent++
+		}
+	}
+	if len(trimmed) < 2 || !strings.ContainsRune("-*+", rune(trimmed[0])) || trimmed[1] != ' ' {
+		return 0, "", false
+	}
+	return indent, strings.TrimSpace(trimmed[2:]), true
+}
@@ -0,0 +1,35 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseADRMarkdownRoundTrip(t *testing.T) {
+	d := Decision{
+		Title:             "Use Echo",
+		Context:           "We need a router.\n\nIt must be fast.",
+		Decision:          "Use Echo for HTTP.",
+		OptionsConsidered: map[string][]string{"echo": {"fast"}, "gin": {"popular", "big"}},
+		Tags:              []string{"web", "go"},
+		Stakeholders:      []string{"@backend", "@sre"},
+	}
+	req, err := ParseADRMarkdown(strings.NewReader(d.Markdown()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Title != d.Title || req.Context != d.Context || req.Decision != d.Decision ||
+		fmt.Sprint(req.OptionsConsidered) != fmt.Sprint(d.OptionsConsidered) ||
+		fmt.Sprint(req.Tags) != fmt.Sprint(d.Tags) ||
+		fmt.Sprint(req.Stakeholders) != fmt.Sprint(d.Stakeholders) {
+		t.Errorf("round trip = %+v", req)
+	}
+
+	_, err = ParseADRMarkdown(strings.NewReader("# T\n\n## Decision\nx\n\n## Options Considered:\nnot a list\n"))
+	var me *MarkdownError
+	if !errors.As(err, &me) || me.Line != 7 || me.Section != "options considered" {
+		t.Errorf("err = %v", err)
+	}
+}
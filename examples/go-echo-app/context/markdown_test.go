@@ -0,0 +1,28 @@
+package context
+
+import (
+	"testing"
+)
+
+func TestDecisionMarkdown(t *testing.T) {
+	d := Decision{
+		Title:    "Use Echo",
+		Status:   "Accepted",
+		Decision: "Use Echo for HTTP.",
+		OptionsConsidered: map[string][]string{
+			"gin":  {"popular"},
+			"echo": {"fast", "small"},
+		},
+		Tags:         []string{"web", "go"},
+		Stakeholders: []string{"@backend"},
+	}
+	want := "# Use Echo\n" +
+		"\n## Status\n\nAccepted\n" +
+		"\n## Decision\n\nUse Echo for HTTP.\n" +
+		"\n## Options Considered\n\n- **echo**\n  - fast\n  - small\n- **gin**\n  - popular\n" +
+		"\n## Tags\n\n`web`, `go`\n" +
+		"\n## Stakeholders\n\n- @backend\n"
+	if got := d.Markdown(); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package context
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ResponseMeta is what a successful response said about the server's
+// state beyond its body.
+type ResponseMeta struct {
+	StatusCode int
+	// RateLimitRemaining is the X-RateLimit-Remaining header, or -1 if the
+	// server didn't send it.
+	RateLimitRemaining int
+	// RateLimitReset is when the rate limit window resets, from
+	// X-RateLimit-Reset; zero if the server didn't send it.
+	RateLimitReset time.Time
+	RequestID      string
+}
+
+// WithResponseMeta fills *meta from the response once the call succeeds,
+// e.g. to back off before the rate limit is hit. It is left untouched when
+// the call fails or Query answers from its cache.
+func WithResponseMeta(meta *ResponseMeta) CallOption {
+	return func(o *callOptions) {
+		o.meta = meta
+	}
+}
+
+func newResponseMeta(resp *http.Response) ResponseMeta {
+	m := ResponseMeta{
+		StatusCode:         resp.StatusCode,
+		RateLimitRemaining: -1,
+		RequestID:          resp.Header.Get("X-Request-ID"),
+	}
+	if n, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		m.RateLimitRemaining = n
+	}
+	if n, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		m.RateLimitReset = rateLimitReset(n, time.Now())
+	}
+	return m
+}
+
+// rateLimitReset interprets X-RateLimit-Reset, which servers send either as
+// a Unix time or as seconds from now.
+func rateLimitReset(n int64, now time.Time) time.Time {
+	const epochCutoff = 1 << 30 // 2004; no window is that long
+	if n >= epochCutoff {
+		return time.Unix(n, 0)
+	}
+	return now.Add(time.Duration(n) * time.Second)
+}
@@ -0,0 +1,34 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponseMeta(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "7")
+		w.Header().Set("X-RateLimit-Reset", "1900000000")
+		w.Header().Set("X-Request-ID", "req-42")
+		json.NewEncoder(w).Encode(QueryResponse{})
+	}))
+	defer srv.Close()
+
+	var meta ResponseMeta
+	if _, err := NewClient(srv.URL).Query(context.Background(), QueryRequest{Query: "q"}, WithResponseMeta(&meta)); err != nil {
+		t.Fatal(err)
+	}
+	want := ResponseMeta{StatusCode: 200, RateLimitRemaining: 7, RateLimitReset: time.Unix(1900000000, 0), RequestID: "req-42"}
+	if meta != want {
+		t.Errorf("meta = %+v", meta)
+	}
+
+	now := time.Now()
+	if got := rateLimitReset(30, now); !got.Equal(now.Add(30 * time.Second)) {
+		t.Errorf("relative reset = %v", got)
+	}
+}
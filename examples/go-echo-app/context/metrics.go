@@ -0,0 +1,21 @@
+package context
+
+import "time"
+
+// MetricsRecorder receives one observation per completed request. op is the
+// client method name (e.g. "Query"); statusCode is 0 when no response was
+// received, in which case err is the transport error. When WithQueryCache
+// is on, ObserveCache is also called once per Query with whether it was
+// answered from the cache.
+type MetricsRecorder interface {
+	ObserveRequest(op string, statusCode int, err error, d time.Duration)
+	ObserveCache(op string, hit bool)
+}
+
+// WithMetricsRecorder reports every request to m. See the contextprom
+// subpackage for a Prometheus implementation.
+func WithMetricsRecorder(m MetricsRecorder) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
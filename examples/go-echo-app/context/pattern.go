@@ -0,0 +1,41 @@
+package context
+
+import (
+	"context"
+	"net/http"
+)
+
+// Pattern classifies a failure for grouping and analytics. The constants
+// cover the common cases; any other string is still accepted, so teams can
+// record custom patterns.
+type Pattern string
+
+const (
+	PatternDatabaseError Pattern = "database_error"
+	PatternTimeout       Pattern = "timeout"
+	PatternValidation    Pattern = "validation"
+	PatternAuth          Pattern = "auth"
+	PatternRateLimit     Pattern = "rate_limit"
+	PatternDependency    Pattern = "dependency_failure"
+	PatternConfiguration Pattern = "configuration"
+	PatternResourceLimit Pattern = "resource_exhaustion"
+)
+
+// ListPatterns returns the patterns the server has seen, for autocomplete.
+func (c *Client) ListPatterns(ctx context.Context, opts ...CallOption) ([]Pattern, error) {
+	var resp struct {
+		Patterns []Pattern `json:"patterns"`
+	}
+	err := c.do(ctx, call{
+		op:     "ListPatterns",
+		method: http.MethodGet,
+		path:   "/failure/patterns",
+		out:    &resp,
+		opts:   opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Patterns, nil
+}
@@ -0,0 +1,37 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestListPatterns(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/failure/patterns" {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"patterns":["timeout","database_error","custom_pattern"]}`))
+	}))
+	defer srv.Close()
+
+	got, err := NewClient(srv.URL).ListPatterns(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Patterns the client has no constant for are kept.
+	if !slices.Equal(got, []Pattern{PatternTimeout, PatternDatabaseError, "custom_pattern"}) {
+		t.Errorf("patterns = %q", got)
+	}
+
+	// The constants are the wire values the server expects.
+	b, _ := json.Marshal(FailureRequest{Pattern: PatternResourceLimit})
+	var wire map[string]any
+	json.Unmarshal(b, &wire)
+	if wire["pattern"] != "resource_exhaustion" {
+		t.Errorf("pattern on the wire = %v", wire["pattern"])
+	}
+}
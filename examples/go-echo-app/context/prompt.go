@@ -0,0 +1,98 @@
+package context
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PromptOptions controls QueryResponse.PromptMarkdown.
+type PromptOptions struct {
+	// IncludeScores appends each item's relevance score.
+	IncludeScores bool
+	// MaxPerSection caps the items rendered per section; 0 means no cap.
+	MaxPerSection int
+}
+
+// PromptMarkdown renders r as Markdown for a system prompt: Key Decisions,
+// Known Issues and Recent Changes sections, each ordered by score
+// descending. Empty sections are left out, so an empty response renders as
+// "".
+func (r *QueryResponse) PromptMarkdown(opts PromptOptions) string {
+	var b strings.Builder
+	section := func(heading string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("## " + heading + "\n\n")
+		for _, l := range lines {
+			b.WriteString("- " + l + "\n")
+		}
+	}
+	suffix := func(id string, score float64) string {
+		if opts.IncludeScores {
+			return fmt.Sprintf(" (%s, score %.2f)", id, score)
+		}
+		return " (" + id + ")"
+	}
+
+	decisions := topByScore(r.KeyDecisions, opts.MaxPerSection, func(d Decision) float64 { return d.Score })
+	var lines []string
+	for _, d := range decisions {
+		line := "**" + d.Title + "**" + suffix(d.ID, d.Score)
+		if d.Decision != "" {
+			line += ": " + oneLine(d.Decision)
+		}
+		lines = append(lines, line)
+	}
+	section("Key Decisions", lines)
+
+	issues := topByScore(r.KnownIssues, opts.MaxPerSection, func(i Issue) float64 { return i.Score })
+	lines = nil
+	for _, i := range issues {
+		line := "**" + i.Title + "**" + suffix(i.ID, i.Score)
+		if i.RootCause != "" {
+			line += ": root cause: " + oneLine(i.RootCause)
+		}
+		if i.Resolution != "" {
+			line += "; resolution: " + oneLine(i.Resolution)
+		}
+		if i.Runbook != "" && !strings.ContainsAny(i.Runbook, "\n") {
+			line += "; runbook: " + i.Runbook
+		}
+		lines = append(lines, line)
+	}
+	section("Known Issues", lines)
+
+	changes := topByScore(r.RecentChanges, opts.MaxPerSection, func(c Change) float64 { return c.Score })
+	lines = nil
+	for _, c := range changes {
+		line := "**" + c.Title + "**" + suffix(c.ID, c.Score)
+		if c.Type != "" {
+			line = "[" + string(c.Type) + "] " + line
+		}
+		lines = append(lines, line)
+	}
+	section("Recent Changes", lines)
+
+	return b.String()
+}
+
+// topByScore returns up to max items of s, highest score first, without
+// modifying s.
+func topByScore[T any](s []T, max int, score func(T) float64) []T {
+	out := append([]T(nil), s...)
+	sort.SliceStable(out, func(i, j int) bool { return score(out[i]) > score(out[j]) })
+	if max > 0 && len(out) > max {
+		out = out[:max]
+	}
+	return out
+}
+
+// oneLine collapses whitespace so multi-line fields stay in one list item.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
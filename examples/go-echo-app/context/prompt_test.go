@@ -0,0 +1,22 @@
+package context
+
+import (
+	"testing"
+)
+
+func TestPromptMarkdown(t *testing.T) {
+	r := QueryResponse{
+		KeyDecisions: []Decision{
+			{ID: "adr-2", Title: "Low", Score: 0.2},
+			{ID: "adr-1", Title: "Use Echo", Decision: "Echo for\nHTTP", Score: 0.9},
+		},
+		KnownIssues:   []Issue{{ID: "f-1", Title: "Dup rows", RootCause: "no index", Score: 0.5}},
+		RecentChanges: []Change{{ID: "c-1", Type: ChangeFix, Title: "Add index"}},
+	}
+	want := "## Key Decisions\n\n- **Use Echo** (adr-1, score 0.90): Echo for HTTP\n" +
+		"\n## Known Issues\n\n- **Dup rows** (f-1, score 0.50): root cause: no index\n" +
+		"\n## Recent Changes\n\n- [fix] **Add index** (c-1, score 0.00)\n"
+	if got := r.PromptMarkdown(PromptOptions{IncludeScores: true, MaxPerSection: 1}); got != want {
+		t.Errorf("PromptMarkdown =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,268 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+const defaultConfidenceThreshold = 0.7
+
+// WithConfidenceThreshold sets the score a decision must exceed before
+// callers should change behavior because of it. See ConfidenceThreshold.
+func WithConfidenceThreshold(score float64) Option {
+	return func(c *Client) {
+		c.confidenceThreshold = score
+	}
+}
+
+// ConfidenceThreshold returns the configured minimum confidence to act,
+// for use with QueryResponse.DecisionsAbove.
+func (c *Client) ConfidenceThreshold() float64 {
+	return c.confidenceThreshold
+}
+
+// DecisionsAbove returns the decisions scoring strictly above score, in
+// response order.
+func (r *QueryResponse) DecisionsAbove(score float64) []Decision {
+	var out []Decision
+	for _, d := range r.KeyDecisions {
+		if d.Score > score {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// DecisionsByDomain buckets the decisions by Domain, each bucket ordered by
+// score descending and then ID. Decisions without a domain are under "".
+func (r *QueryResponse) DecisionsByDomain() map[string][]Decision {
+	out := make(map[string][]Decision)
+	for _, d := range r.KeyDecisions {
+		out[d.Domain] = append(out[d.Domain], d)
+	}
+	for _, ds := range out {
+		sort.SliceStable(ds, func(i, j int) bool { return keysetLess(ds[i], ds[j]) })
+	}
+	return out
+}
+
+// QueryBuilder builds a QueryRequest. Each method returns a new builder and
+// leaves the receiver unchanged, so a base builder can be shared:
+//
+//	base := NewQuery("user validation").WithDomains(DomainUsers)
+//	strict := base.WithMinScore(0.8).Build()
+type QueryBuilder struct {
+	req QueryRequest
+}
+
+func NewQuery(query string) QueryBuilder {
+	return QueryBuilder{req: QueryRequest{Query: query}}
+}
+
+// WithDomains appends to the domains already set on b.
+func (b QueryBuilder) WithDomains(domains ...string) QueryBuilder {
+	b.req.Domains = append(append([]string(nil), b.req.Domains...), domains...)
+	return b
+}
+
+// WithLimit sets MaxItems.
+func (b QueryBuilder) WithLimit(n int) QueryBuilder {
+	b.req.MaxItems = n
+	return b
+}
+
+func (b QueryBuilder) WithMaxTokens(n int) QueryBuilder {
+	b.req.MaxTokens = n
+	return b
+}
+
+func (b QueryBuilder) WithMinScore(score float64) QueryBuilder {
+	b.req.MinScore = score
+	return b
+}
+
+func (b QueryBuilder) WithVisibility(v Visibility) QueryBuilder {
+	b.req.Visibility = v
+	return b
+}
+
+func (b QueryBuilder) WithFacets() QueryBuilder {
+	b.req.IncludeFacets = true
+	return b
+}
+
+// WithEmbedding sets Embedding. The vector is not copied.
+func (b QueryBuilder) WithEmbedding(v []float32) QueryBuilder {
+	b.req.Embedding = v
+	return b
+}
+
+// WithRecencyBoost sets RecencyBoost.
+func (b QueryBuilder) WithRecencyBoost(boost float64) QueryBuilder {
+	b.req.RecencyBoost = boost
+	return b
+}
+
+// WithWeight sets the weight for kind in Weights.
+func (b QueryBuilder) WithWeight(kind Kind, w float64) QueryBuilder {
+	weights := maps.Clone(b.req.Weights)
+	if weights == nil {
+		weights = map[Kind]float64{}
+	}
+	weights[kind] = w
+	b.req.Weights = weights
+	return b
+}
+
+func (b QueryBuilder) WithSort(by SortBy, order Order) QueryBuilder {
+	b.req.SortBy = by
+	b.req.Order = order
+	return b
+}
+
+// Build returns the request. Its slices and maps are not shared with b.
+func (b QueryBuilder) Build() QueryRequest {
+	req := b.req
+	req.Domains = append([]string(nil), b.req.Domains...)
+	req.Weights = maps.Clone(b.req.Weights)
+	return req
+}
+
+// Validate checks that SortBy and Order name known values, that Order is
+// only set together with SortBy, that Cursor is not combined with SortBy,
+// that the created range is not inverted, that Embedding is finite, that
+// RecencyBoost is between 0 and 1 and that Weights are finite and not
+// negative.
+func (r QueryRequest) Validate() error {
+	v := newValidator("QueryRequest")
+	v.timeRange("created_after", "created_before", r.CreatedAfter, r.CreatedBefore)
+	if r.Cursor != "" && r.SortBy != "" {
+		v.add("cursor", "cannot be combined with sort")
+	}
+	if r.SortBy != "" && !r.SortBy.valid() {
+		v.add("sort", fmt.Sprintf("unknown value %q", r.SortBy))
+	}
+	if r.Order != "" {
+		if !r.Order.valid() {
+			v.add("order", fmt.Sprintf("unknown value %q", r.Order))
+		} else if r.SortBy == "" {
+			v.add("order", "requires sort")
+		}
+	}
+	for i, x := range r.Embedding {
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			v.add("embedding", fmt.Sprintf("value %d is not finite", i))
+			break
+		}
+	}
+	if !(r.RecencyBoost >= 0 && r.RecencyBoost <= 1) {
+		v.add("recency_boost", "must be between 0 and 1")
+	}
+	kinds := make([]Kind, 0, len(r.Weights))
+	for kind := range r.Weights {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	for _, kind := range kinds {
+		if w := r.Weights[kind]; !(w >= 0) || math.IsInf(w, 1) {
+			v.add(fmt.Sprintf("weights[%q]", kind), "must be a finite number, 0 or more")
+		}
+	}
+	return v.err()
+}
+
+func (r QueryRequest) values() url.Values {
+	q := url.Values{}
+	setTimeRange(q, r.CreatedAfter, r.CreatedBefore)
+	if r.SortBy != "" {
+		q.Set("sort", string(r.SortBy))
+		q.Set("order", string(r.orderOrDefault()))
+	}
+	return q
+}
+
+func (r QueryRequest) orderOrDefault() Order {
+	if r.Order == "" {
+		return OrderDesc
+	}
+	return r.Order
+}
+
+// BestDecision returns the highest-scoring decision for query if its score is
+// at least minScore. The bool reports whether one was found. Only a single
+// item is requested from servers that honor MaxItems.
+func (c *Client) BestDecision(ctx context.Context, query string, minScore float64, domains ...string) (*Decision, bool, error) {
+	resp, err := c.Query(ctx, QueryRequest{
+		Query:    query,
+		MaxItems: 1,
+		MinScore: minScore,
+		Domains:  domains,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var best *Decision
+	for i := range resp.KeyDecisions {
+		d := &resp.KeyDecisions[i]
+		if d.Score >= minScore && (best == nil || d.Score > best.Score) {
+			best = d
+		}
+	}
+	if best == nil {
+		return nil, false, nil
+	}
+	return best, true, nil
+}
+
+// CountQuery returns how many items match req without fetching them. It
+// sends count_only=true so the server can skip the item bodies; if the
+// server sends them anyway they are not decoded.
+func (c *Client) CountQuery(ctx context.Context, req QueryRequest, opts ...CallOption) (int, error) {
+	if err := req.Validate(); err != nil {
+		return 0, err
+	}
+	if c.validateDomains {
+		if err := c.ValidateDomains(req.Domains); err != nil {
+			return 0, err
+		}
+	}
+	body, err := c.embed(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	q := req.values()
+	q.Set("count_only", "true")
+	// Every QueryResponse field, so strict decoding accepts a full
+	// response, but only TotalItems is decoded.
+	var result struct {
+		KeyDecisions  json.RawMessage `json:"key_decisions"`
+		KnownIssues   json.RawMessage `json:"known_issues"`
+		RecentChanges json.RawMessage `json:"recent_changes"`
+		TotalItems    int             `json:"total_items"`
+		NextCursor    json.RawMessage `json:"next_cursor"`
+		Facets        json.RawMessage `json:"facets"`
+		QueryID       json.RawMessage `json:"query_id"`
+	}
+	err = c.do(ctx, call{
+		op:     "CountQuery",
+		method: http.MethodPost,
+		path:   "/context/query",
+		query:  q,
+		in:     body,
+		out:    &result,
+		opts:   opts,
+		read:   true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.TotalItems, nil
+}
@@ -0,0 +1,248 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreatedRange(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		fmt.Fprint(w, `[]`)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	apr := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		after, before time.Time
+		want          url.Values
+		wantErr       bool
+	}{
+		{name: "only after", after: jan, want: url.Values{"created_after": {"2024-01-01T00:00:00Z"}}},
+		{name: "only before", before: apr, want: url.Values{"created_before": {"2024-04-01T00:00:00Z"}}},
+		{name: "bounded", after: jan, before: apr, want: url.Values{
+			"created_after":  {"2024-01-01T00:00:00Z"},
+			"created_before": {"2024-04-01T00:00:00Z"},
+		}},
+		{name: "inverted", after: apr, before: jan, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			_, err := c.ListFailures(context.Background(), FailureFilter{CreatedAfter: tt.after, CreatedBefore: tt.before})
+			var ve *ValidationError
+			if tt.wantErr {
+				if !errors.As(err, &ve) || got != nil {
+					t.Fatalf("err = %v, sent = %v; want validation error before sending", err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Encode() != tt.want.Encode() {
+				t.Errorf("query = %q, want %q", got.Encode(), tt.want.Encode())
+			}
+		})
+	}
+
+	_, err := c.Query(context.Background(), QueryRequest{Query: "q", CreatedAfter: apr, CreatedBefore: jan})
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Errorf("Query err = %v, want *ValidationError", err)
+	}
+}
+
+func TestCountQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("count_only") != "true" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"key_decisions":[{"id":"ADR-1"}],"known_issues":[],"recent_changes":[],"total_items":6,"next_cursor":"c","facets":{"tag":[]}}`))
+	}))
+	defer srv.Close()
+
+	n, err := NewClient(srv.URL, WithStrictDecoding(true)).CountQuery(context.Background(), QueryRequest{Query: "q"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 6 {
+		t.Errorf("count = %d", n)
+	}
+}
+
+func TestBestDecision(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req QueryRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.MaxItems != 1 {
+			t.Errorf("max_items = %d", req.MaxItems)
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	for _, tt := range []struct {
+		name, body string
+		minScore   float64
+		want       string // "" for no match
+	}{
+		{"empty", `{"key_decisions":[]}`, 0.5, ""},
+		// The first of equal scores wins.
+		{"tie", `{"key_decisions":[{"id":"a","score":0.8},{"id":"b","score":0.8}]}`, 0.5, "a"},
+		{"highest", `{"key_decisions":[{"id":"a","score":0.6},{"id":"b","score":0.9}]}`, 0.5, "b"},
+		// A server ignoring min_score doesn't let weaker matches through.
+		{"below min", `{"key_decisions":[{"id":"a","score":0.4}]}`, 0.5, ""},
+		{"at min", `{"key_decisions":[{"id":"a","score":0.5}]}`, 0.5, "a"},
+	} {
+		body = tt.body
+		d, ok, err := c.BestDecision(context.Background(), "q "+tt.name, tt.minScore)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := ""
+		if ok {
+			got = d.ID
+		}
+		if got != tt.want || ok != (d != nil) {
+			t.Errorf("%s: got %q, %v, want %q", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+func TestMinScoreFilter(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Ignores min_score, max_items and cursor.
+		requests++
+		fmt.Fprint(w, `{"key_decisions":[{"id":"a","score":0.9},{"id":"b","score":0.4},
+			{"id":"c","score":0.7},{"id":"d","score":0.3},{"id":"e","score":0.6}]}`)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, WithQueryCache(time.Minute))
+	ids := func(ds []Decision) string {
+		var s []string
+		for _, d := range ds {
+			s = append(s, d.ID)
+		}
+		return strings.Join(s, ",")
+	}
+
+	// The filter runs before paging, so a page is filled from matches.
+	resp, err := c.Query(context.Background(), QueryRequest{Query: "q", MinScore: 0.5, MaxItems: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(resp.KeyDecisions); got != "a,c" {
+		t.Errorf("first page = %s", got)
+	}
+	all, err := c.QueryAll(context.Background(), QueryRequest{Query: "q", MinScore: 0.5, MaxItems: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(all); got != "a,c,e" {
+		t.Errorf("QueryAll = %s", got)
+	}
+
+	// MinScore is part of the cache key: a looser query isn't served the
+	// filtered result, and the filtered one is served from cache.
+	requests = 0
+	unfiltered, err := c.Query(context.Background(), QueryRequest{Query: "q"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered, err := c.Query(context.Background(), QueryRequest{Query: "q", MinScore: 0.5, MaxItems: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unfiltered.KeyDecisions) != 5 || ids(filtered.KeyDecisions) != "a,c" || requests != 1 {
+		t.Errorf("unfiltered %d decisions, filtered %s, %d requests", len(unfiltered.KeyDecisions), ids(filtered.KeyDecisions), requests)
+	}
+}
+
+func TestDecisionsAbove(t *testing.T) {
+	c := NewClient("http://ctx.example", WithConfidenceThreshold(0.8))
+	if got := NewClient("http://ctx.example").ConfidenceThreshold(); got != defaultConfidenceThreshold {
+		t.Errorf("default threshold = %v", got)
+	}
+	resp := &QueryResponse{KeyDecisions: []Decision{
+		{ID: "at", Score: 0.8}, {ID: "above", Score: 0.81}, {ID: "below", Score: 0.79}, {ID: "top", Score: 1},
+	}}
+	// A score equal to the threshold does not pass.
+	var ids []string
+	for _, d := range resp.DecisionsAbove(c.ConfidenceThreshold()) {
+		ids = append(ids, d.ID)
+	}
+	if got := strings.Join(ids, ","); got != "above,top" {
+		t.Errorf("DecisionsAbove = %s", got)
+	}
+}
+
+func TestQueryBuilderBranches(t *testing.T) {
+	base := NewQuery("q").WithDomains(DomainUsers).WithWeight(KindDecision, 2)
+	a := base.WithDomains(DomainAPI).WithWeight(KindIssue, 0.5).WithMinScore(0.8)
+	b := base.WithDomains(DomainSecurity).WithLimit(5)
+
+	ra, rb, rbase := a.Build(), b.Build(), base.Build()
+	if got := strings.Join(ra.Domains, ","); got != "users,api" {
+		t.Errorf("a domains = %s", got)
+	}
+	if got := strings.Join(rb.Domains, ","); got != "users,security" {
+		t.Errorf("b domains = %s", got)
+	}
+	if len(rbase.Domains) != 1 || len(rbase.Weights) != 1 || rbase.MinScore != 0 || rbase.MaxItems != 0 {
+		t.Errorf("base changed: %+v", rbase)
+	}
+	if len(ra.Weights) != 2 || len(rb.Weights) != 1 || rb.MinScore != 0 || ra.MaxItems != 0 {
+		t.Errorf("branches leaked: a %+v, b %+v", ra, rb)
+	}
+
+	// A built request doesn't alias the builder.
+	ra.Domains[0] = "changed"
+	ra.Weights[KindDecision] = 9
+	if again := a.Build(); again.Domains[0] != DomainUsers || again.Weights[KindDecision] != 2 {
+		t.Errorf("Build aliases builder: %+v", again)
+	}
+}
+
+func TestDecisionsByDomain(t *testing.T) {
+	var resp QueryResponse
+	err := json.Unmarshal([]byte(`{"key_decisions":[
+		{"id":"b","score":0.5,"domain":"users"},
+		{"id":"x","score":0.9},
+		{"id":"a","score":0.5,"domain":"users"},
+		{"id":"c","score":0.8,"domain":"users"},
+		{"id":"s","score":0.1,"domain":"security"}]}`), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := func(ds []Decision) string {
+		var s []string
+		for _, d := range ds {
+			s = append(s, d.ID)
+		}
+		return strings.Join(s, ",")
+	}
+
+	by := resp.DecisionsByDomain()
+	if len(by) != 3 || ids(by[DomainUsers]) != "c,a,b" || ids(by[DomainSecurity]) != "s" || ids(by[""]) != "x" {
+		t.Errorf("by domain = %v", by)
+	}
+	if ids(resp.KeyDecisions) != "b,x,a,c,s" {
+		t.Errorf("KeyDecisions reordered: %s", ids(resp.KeyDecisions))
+	}
+}
@@ -0,0 +1,98 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// QueryStream runs req against /context/query/stream, which answers with
+// one JSON decision per line, and sends each decision on the first channel
+// as soon as it is decoded, so large results needn't be held in memory.
+// The error channel receives at most one value; both channels are closed
+// when the stream ends. Cancelling ctx stops decoding, closes the
+// connection and closes both channels without an error. Servers without
+// the endpoint get a plain Query whose decisions are then sent one by one.
+func (c *Client) QueryStream(ctx context.Context, req QueryRequest, opts ...CallOption) (<-chan Decision, <-chan error) {
+	decisions := make(chan Decision)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(decisions)
+		err := c.queryStream(ctx, req, opts, decisions)
+		if isUnsupported(err) {
+			var resp *QueryResponse
+			resp, err = c.Query(ctx, req, opts...)
+			if err == nil {
+				err = sendAll(ctx, resp.KeyDecisions, decisions)
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			errc <- err
+		}
+	}()
+
+	return decisions, errc
+}
+
+func (c *Client) queryStream(ctx context.Context, req QueryRequest, opts []CallOption, out chan<- Decision) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	if c.validateDomains {
+		if err := c.ValidateDomains(req.Domains); err != nil {
+			return err
+		}
+	}
+	body, err := c.embed(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, call{
+		op:          "QueryStream",
+		method:      http.MethodPost,
+		path:        "/context/query/stream",
+		query:       req.values(),
+		in:          body,
+		opts:        opts,
+		read:        true,
+		contentType: "application/json",
+		accept:      "application/x-ndjson",
+		stream: func(r io.Reader) error {
+			dec := json.NewDecoder(r)
+			for {
+				var d Decision
+				if err := dec.Decode(&d); err != nil {
+					if errors.Is(err, io.EOF) {
+						return nil
+					}
+					return fmt.Errorf("decode decision stream: %w", err)
+				}
+				// Not every server honors min_score; see Query.
+				if req.MinScore > 0 && d.Score < req.MinScore {
+					continue
+				}
+				select {
+				case out <- d:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		},
+	})
+}
+
+func sendAll(ctx context.Context, decisions []Decision, out chan<- Decision) error {
+	for _, d := range decisions {
+		select {
+		case out <- d:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryStream(t *testing.T) {
+	closed := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/context/query/stream" || r.Header.Get("Accept") != "application/x-ndjson" {
+			t.Errorf("%s, Accept %q", r.URL.Path, r.Header.Get("Accept"))
+		}
+		for _, id := range []string{"adr-1", "adr-2"} {
+			fmt.Fprintf(w, "{\"id\":%q}\n", id)
+			w.(http.Flusher).Flush()
+		}
+		<-r.Context().Done()
+		close(closed)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	decisions, errc := NewClient(srv.URL).QueryStream(ctx, QueryRequest{Query: "q"})
+	for _, want := range []string{"adr-1", "adr-2"} {
+		select {
+		case d := <-decisions:
+			if d.ID != want {
+				t.Errorf("got %s, want %s", d.ID, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no %s before the response ended", want)
+		}
+	}
+	cancel()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("connection not closed after cancel")
+	}
+	if _, ok := <-decisions; ok {
+		t.Error("decisions channel still open")
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestQueryStreamFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/context/query/stream" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"key_decisions":[{"id":"adr-1"},{"id":"adr-2"}]}`))
+	}))
+	defer srv.Close()
+
+	decisions, errc := NewClient(srv.URL).QueryStream(context.Background(), QueryRequest{Query: "q"})
+	var ids []string
+	for d := range decisions {
+		ids = append(ids, d.ID)
+	}
+	if err := <-errc; err != nil || strings.Join(ids, ",") != "adr-1,adr-2" {
+		t.Errorf("ids = %v, err = %v", ids, err)
+	}
+}
+
+func TestQueryStreamSharedPath(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+	var gotID string
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = r.Header.Get("X-Request-ID")
+		fmt.Fprint(w, "{\"id\":\"adr-1\"}\n")
+	}))
+	defer secondary.Close()
+
+	var infos []RequestInfo
+	c := NewClientWithEndpoints([]string{primary.URL, secondary.URL}, WithLogger(func(ri RequestInfo) { infos = append(infos, ri) }))
+	decisions, errc := c.QueryStream(context.Background(), QueryRequest{Query: "q"}, WithRequestID("rid-1"))
+	var ids []string
+	for d := range decisions {
+		ids = append(ids, d.ID)
+	}
+	if err := <-errc; err != nil || strings.Join(ids, ",") != "adr-1" {
+		t.Fatalf("ids = %v, err = %v", ids, err)
+	}
+	if gotID != "rid-1" {
+		t.Errorf("X-Request-ID = %q", gotID)
+	}
+	if len(infos) != 1 || infos[0].Method != "QueryStream" || infos[0].StatusCode != http.StatusOK {
+		t.Errorf("logged %+v", infos)
+	}
+	if n := c.Stats().Requests; n != 1 {
+		t.Errorf("Stats().Requests = %d", n)
+	}
+}
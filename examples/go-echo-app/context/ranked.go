@@ -0,0 +1,40 @@
+package context
+
+import "sort"
+
+// Ranked merges r's decisions, issues and changes into one list, highest
+// first, scored by each item's Score times the weight for its kind. Kinds
+// missing from weights count at weight 1, so nil weights merge by raw
+// score; kinds weighted 0 are left out. Ties keep decisions before issues
+// before changes. It is the client-side counterpart of
+// QueryRequest.Weights. Payloads point to copies of r's items.
+func (r *QueryResponse) Ranked(weights map[Kind]float64) []SearchHit {
+	weight := func(k Kind) float64 {
+		if w, ok := weights[k]; ok {
+			return w
+		}
+		return 1
+	}
+
+	hits := make([]SearchHit, 0, len(r.KeyDecisions)+len(r.KnownIssues)+len(r.RecentChanges))
+	if w := weight(KindDecision); w != 0 {
+		for _, d := range r.KeyDecisions {
+			d := d
+			hits = append(hits, SearchHit{Kind: KindDecision, Score: w * d.Score, Payload: &d})
+		}
+	}
+	if w := weight(KindIssue); w != 0 {
+		for _, i := range r.KnownIssues {
+			i := i
+			hits = append(hits, SearchHit{Kind: KindIssue, Score: w * i.Score, Payload: &i})
+		}
+	}
+	if w := weight(KindChange); w != 0 {
+		for _, ch := range r.RecentChanges {
+			ch := ch
+			hits = append(hits, SearchHit{Kind: KindChange, Score: w * ch.Score, Payload: &ch})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	return hits
+}
@@ -0,0 +1,76 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRanked(t *testing.T) {
+	r := &QueryResponse{
+		KeyDecisions:  []Decision{{ID: "d", Score: 0.6}},
+		KnownIssues:   []Issue{{ID: "i", Score: 0.7}},
+		RecentChanges: []Change{{ID: "c", Score: 0.9}, {ID: "c2", Score: 0.1}},
+	}
+	ids := func(hits []SearchHit) []string {
+		var out []string
+		for _, h := range hits {
+			switch p := h.Payload.(type) {
+			case *Decision:
+				out = append(out, p.ID)
+			case *Issue:
+				out = append(out, p.ID)
+			case *Change:
+				out = append(out, p.ID)
+			}
+		}
+		return out
+	}
+
+	if got := ids(r.Ranked(nil)); !slices.Equal(got, []string{"c", "i", "d", "c2"}) {
+		t.Errorf("unweighted = %q", got)
+	}
+	hits := r.Ranked(map[Kind]float64{KindDecision: 1, KindIssue: 0.8, KindChange: 0.3})
+	if got := ids(hits); !slices.Equal(got, []string{"d", "i", "c", "c2"}) {
+		t.Errorf("weighted = %q", got)
+	}
+	if math.Abs(hits[2].Score-0.27) > 1e-9 || hits[2].Kind != KindChange {
+		t.Errorf("change hit = %+v", hits[2])
+	}
+	if got := ids(r.Ranked(map[Kind]float64{KindChange: 0})); !slices.Equal(got, []string{"i", "d"}) {
+		t.Errorf("changes weighted 0 = %q", got)
+	}
+}
+
+func TestQueryWeights(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	b := NewQuery("q").WithWeight(KindDecision, 1).WithWeight(KindChange, 0.3)
+	req := b.Build()
+	req.Weights[KindIssue] = 0.8 // must not leak into b
+	if _, err := c.Query(context.Background(), b.Build()); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"decision": 1.0, "change": 0.3}
+	if w, _ := got["weights"].(map[string]any); len(w) != 2 || w["decision"] != want["decision"] || w["change"] != want["change"] {
+		t.Errorf("weights = %v", got["weights"])
+	}
+
+	var verr *ValidationError
+	_, err := c.Query(context.Background(), QueryRequest{Query: "q", Weights: map[Kind]float64{KindIssue: -1}})
+	if !errors.As(err, &verr) || !strings.Contains(err.Error(), `weights["issue"]`) {
+		t.Errorf("negative weight: err = %v", err)
+	}
+}
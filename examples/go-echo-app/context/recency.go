@@ -0,0 +1,79 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RerankByRecency reorders each of r's lists, highest first, by
+// (1-weight)*Score + weight*recency, where recency runs from 0 for the
+// list's oldest item to 1 for its newest. A weight of 0 sorts by Score
+// alone and 1 by CreatedAt alone. It is the client-side stand-in for
+// QueryRequest.RecencyBoost and needs CreatedAt on every item; if one is
+// missing, or weight is outside 0..1, r is left as it was.
+func (r *QueryResponse) RerankByRecency(weight float64) error {
+	if !(weight >= 0 && weight <= 1) {
+		return fmt.Errorf("rerank by recency: weight %v is not between 0 and 1", weight)
+	}
+	err := errors.Join(
+		checkCreatedAt(r.KeyDecisions, func(d Decision) (string, time.Time) { return d.ID, d.CreatedAt }),
+		checkCreatedAt(r.KnownIssues, func(i Issue) (string, time.Time) { return i.ID, i.CreatedAt }),
+		checkCreatedAt(r.RecentChanges, func(ch Change) (string, time.Time) { return ch.ID, ch.CreatedAt }),
+	)
+	if err != nil {
+		return err
+	}
+	rerank(r.KeyDecisions, weight, func(d Decision) (float64, time.Time) { return d.Score, d.CreatedAt })
+	rerank(r.KnownIssues, weight, func(i Issue) (float64, time.Time) { return i.Score, i.CreatedAt })
+	rerank(r.RecentChanges, weight, func(ch Change) (float64, time.Time) { return ch.Score, ch.CreatedAt })
+	return nil
+}
+
+func checkCreatedAt[T any](items []T, key func(T) (string, time.Time)) error {
+	for _, it := range items {
+		if id, t := key(it); t.IsZero() {
+			return fmt.Errorf("rerank by recency: %s has no created_at", id)
+		}
+	}
+	return nil
+}
+
+// rerank stably sorts items by their blended score, highest first.
+func rerank[T any](items []T, weight float64, key func(T) (float64, time.Time)) {
+	if len(items) < 2 {
+		return
+	}
+	_, oldest := key(items[0])
+	newest := oldest
+	for _, it := range items[1:] {
+		_, t := key(it)
+		if t.Before(oldest) {
+			oldest = t
+		}
+		if t.After(newest) {
+			newest = t
+		}
+	}
+	span := newest.Sub(oldest).Seconds()
+
+	blended := make([]float64, len(items))
+	order := make([]int, len(items))
+	for i, it := range items {
+		score, t := key(it)
+		recency := 1.0
+		if span > 0 {
+			recency = t.Sub(oldest).Seconds() / span
+		}
+		blended[i] = (1-weight)*score + weight*recency
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return blended[order[a]] > blended[order[b]] })
+
+	sorted := make([]T, len(items))
+	for i, j := range order {
+		sorted[i] = items[j]
+	}
+	copy(items, sorted)
+}
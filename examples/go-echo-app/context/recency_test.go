@@ -0,0 +1,83 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecencyBoost(t *testing.T) {
+	var got QueryRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	if _, err := c.Query(context.Background(), NewQuery("q").WithRecencyBoost(0.3).Build()); err != nil {
+		t.Fatal(err)
+	}
+	if got.RecencyBoost != 0.3 {
+		t.Errorf("recency_boost = %v", got.RecencyBoost)
+	}
+	var verr *ValidationError
+	if _, err := c.Query(context.Background(), QueryRequest{Query: "q", RecencyBoost: 1.5}); !errors.As(err, &verr) {
+		t.Errorf("boost 1.5: err = %v", err)
+	}
+}
+
+func TestRerankByRecency(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2026, 1, n, 0, 0, 0, 0, time.UTC) }
+	resp := func() *QueryResponse {
+		return &QueryResponse{
+			KeyDecisions: []Decision{
+				{ID: "old-relevant", Score: 0.9, CreatedAt: day(1)},
+				{ID: "new", Score: 0.5, CreatedAt: day(11)},
+				{ID: "mid", Score: 0.7, CreatedAt: day(6)},
+			},
+			RecentChanges: []Change{{ID: "c", CreatedAt: day(2)}},
+		}
+	}
+	ids := func(r *QueryResponse) []string {
+		var out []string
+		for _, d := range r.KeyDecisions {
+			out = append(out, d.ID)
+		}
+		return out
+	}
+	for _, tc := range []struct {
+		weight float64
+		want   []string
+	}{
+		{0, []string{"old-relevant", "mid", "new"}},
+		{0.5, []string{"new", "mid", "old-relevant"}}, // 0.75, 0.6, 0.45
+		{1, []string{"new", "mid", "old-relevant"}},
+	} {
+		r := resp()
+		if err := r.RerankByRecency(tc.weight); err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(r); !slices.Equal(got, tc.want) {
+			t.Errorf("weight %v: order = %q, want %q", tc.weight, got, tc.want)
+		}
+	}
+
+	r := resp()
+	r.KnownIssues = []Issue{{ID: "i"}}
+	if err := r.RerankByRecency(0.5); err == nil || !strings.Contains(err.Error(), "i has no created_at") {
+		t.Errorf("missing created_at: err = %v", err)
+	}
+	if got := ids(r); !slices.Equal(got, ids(resp())) {
+		t.Errorf("reordered despite error: %q", got)
+	}
+	if err := resp().RerankByRecency(2); err == nil {
+		t.Error("weight 2 accepted")
+	}
+}
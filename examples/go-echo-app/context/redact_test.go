@@ -0,0 +1,20 @@
+package context
+
+import (
+	"testing"
+)
+
+func TestDefaultRedactor(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"dial postgres://app:hunter2@db:5432/users", "dial postgres://app:[REDACTED]@db:5432/users"},
+		{"host=db password=hunter2 sslmode=off", "host=db password=[REDACTED] sslmode=off"},
+		{`{"api_key": "abc123"}`, `{"api_key": "[REDACTED]"}`},
+		{"Authorization: Bearer eyJhbGciOi.x.y", "Authorization: Bearer [REDACTED]"},
+		{"nothing secret here", "nothing secret here"},
+	}
+	for _, tt := range tests {
+		if got := DefaultRedactor(tt.in); got != tt.want {
+			t.Errorf("DefaultRedactor(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package context
+
+// WithRequestIDFunc replaces the UUIDv4 generator for the X-Request-ID
+// header sent with every request. The ID is generated once per call and
+// reused across retries.
+func WithRequestIDFunc(fn func() string) Option {
+	return func(c *Client) {
+		c.requestID = fn
+	}
+}
+
+// WithRequestID sends id as X-Request-ID instead of a generated one, e.g.
+// to carry the ID of the inbound request being served.
+func WithRequestID(id string) CallOption {
+	return func(o *callOptions) {
+		o.requestID = id
+	}
+}
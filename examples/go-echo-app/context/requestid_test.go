@@ -0,0 +1,52 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("X-Request-ID"))
+		if len(seen) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-Request-ID", "srv-"+r.Header.Get("X-Request-ID"))
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	var info RequestInfo
+	c := NewClient(srv.URL,
+		WithRequestIDFunc(func() string { return "rid-1" }),
+		WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+		WithLogger(func(i RequestInfo) { info = i }),
+	)
+	_, err := c.Query(context.Background(), QueryRequest{Query: "q"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "srv-rid-1" {
+		t.Fatalf("err = %v", err)
+	}
+	if !strings.Contains(err.Error(), "srv-rid-1") {
+		t.Errorf("error %q lacks the request id", err)
+	}
+	if len(seen) != 2 || seen[0] != "rid-1" || seen[1] != "rid-1" {
+		t.Errorf("sent ids = %v", seen)
+	}
+	if info.RequestID != "srv-rid-1" {
+		t.Errorf("RequestInfo.RequestID = %q", info.RequestID)
+	}
+
+	seen = nil
+	_, _ = c.ListDomains(context.Background(), WithRequestID("inbound-7"))
+	if len(seen) == 0 || seen[0] != "inbound-7" {
+		t.Errorf("sent ids = %v", seen)
+	}
+}
@@ -0,0 +1,92 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy retries requests that failed with a transport error, a 429 or
+// a 5xx status, using exponential backoff with jitter. Only idempotent HTTP
+// methods and writes carrying an Idempotency-Key are retried.
+type RetryPolicy struct {
+	MaxAttempts int // total attempts including the first; <= 1 disables retries
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy is a reasonable starting point for WithRetry.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// WithRetry enables retries. Clients don't retry by default.
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithNoRetry sends the call exactly once, whatever the client's policy.
+// Call options always take precedence over client options; WithRetry only
+// determines behavior for calls that don't override it.
+func WithNoRetry() CallOption {
+	return func(o *callOptions) {
+		o.noRetry = true
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// backoff returns the wait before the attempt following attempt n, honoring a
+// Retry-After header in seconds when the server sends one.
+func (p RetryPolicy) backoff(n int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return min(time.Duration(secs)*time.Second, p.maxDelay())
+		}
+	}
+
+	d := p.BaseDelay << (n - 1)
+	if d <= 0 || d > p.maxDelay() {
+		d = p.maxDelay()
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func (p RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay > 0 {
+		return p.MaxDelay
+	}
+	return DefaultRetryPolicy.MaxDelay
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
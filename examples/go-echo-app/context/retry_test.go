@@ -0,0 +1,68 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryReusesIdempotencyKey(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	if err := c.RecordFailure(context.Background(), FailureRequest{Title: "t", RootCause: "rc", Severity: "low"}); err != nil {
+		t.Fatalf("RecordFailure: %v", err)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(keys))
+	}
+	if keys[0] == "" || keys[0] != keys[1] || keys[1] != keys[2] {
+		t.Errorf("keys = %q, want one key reused across attempts", keys)
+	}
+}
+
+func TestRetrySkipsUnkeyedWrites(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	_ = c.Do(context.Background(), http.MethodPost, "/events/deploy", map[string]string{}, nil)
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 for an unkeyed POST", attempts)
+	}
+}
+
+func TestNoRetry(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	_ = c.Do(context.Background(), http.MethodGet, "/health", nil, nil, WithNoRetry())
+	if attempts != 1 {
+		t.Errorf("with WithNoRetry: attempts = %d, want 1", attempts)
+	}
+	attempts = 0
+	_ = c.Do(context.Background(), http.MethodGet, "/health", nil, nil)
+	if attempts != 3 {
+		t.Errorf("without WithNoRetry: attempts = %d, want 3", attempts)
+	}
+}
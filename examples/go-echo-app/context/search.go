@@ -0,0 +1,101 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SearchRequest is a query across every kind of record. Kinds restricts the
+// hits to the given kinds; empty means all.
+type SearchRequest struct {
+	Query    string  `json:"query"`
+	Kinds    []Kind  `json:"kinds,omitempty"`
+	Limit    int     `json:"limit,omitempty"`
+	MinScore float64 `json:"min_score,omitempty"`
+}
+
+type SearchResponse struct {
+	Hits  []SearchHit `json:"hits"`
+	Total int         `json:"total"`
+}
+
+// SearchHit is one ranked result. Payload holds a *Decision, *Issue or
+// *Change according to Kind, or the raw json.RawMessage for kinds this client
+// doesn't know:
+//
+//	switch p := hit.Payload.(type) {
+//	case *Decision:
+//	case *Issue:
+//	case *Change:
+//	}
+type SearchHit struct {
+	Kind    Kind    `json:"kind"`
+	Score   float64 `json:"score"`
+	Payload any     `json:"payload"`
+}
+
+func (h *SearchHit) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Kind    Kind            `json:"kind"`
+		Score   float64         `json:"score"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var payload any
+	switch raw.Kind {
+	case KindDecision:
+		payload = new(Decision)
+	case KindIssue:
+		payload = new(Issue)
+	case KindChange:
+		payload = new(Change)
+	default:
+		// Leave unknown kinds undecoded so newer servers don't break
+		// older clients.
+		*h = SearchHit{Kind: raw.Kind, Score: raw.Score, Payload: raw.Payload}
+		return nil
+	}
+	if err := json.Unmarshal(raw.Payload, payload); err != nil {
+		return fmt.Errorf("search hit %s: %w", raw.Kind, err)
+	}
+	*h = SearchHit{Kind: raw.Kind, Score: raw.Score, Payload: payload}
+	return nil
+}
+
+// Search returns hits of every requested kind in one ranked list. Hits of
+// kinds not in req.Kinds are dropped even if the server returns them.
+func (c *Client) Search(ctx context.Context, req SearchRequest, opts ...CallOption) (SearchResponse, error) {
+	var resp SearchResponse
+	err := c.do(ctx, call{
+		op:     "Search",
+		method: http.MethodPost,
+		path:   "/search",
+		in:     req,
+		out:    &resp,
+		opts:   opts,
+		read:   true,
+	})
+	if err != nil {
+		return SearchResponse{}, err
+	}
+
+	if len(req.Kinds) > 0 {
+		want := make(map[Kind]bool, len(req.Kinds))
+		for _, k := range req.Kinds {
+			want[k] = true
+		}
+		kept := resp.Hits[:0]
+		for _, h := range resp.Hits {
+			if want[h.Kind] {
+				kept = append(kept, h)
+			}
+		}
+		resp.Hits = kept
+	}
+	return resp, nil
+}
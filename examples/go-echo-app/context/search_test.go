@@ -0,0 +1,36 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchDecodesPayloadByKind(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"hits":[
+			{"kind":"issue","score":0.9,"payload":{"id":"f-1","root_cause":"rc"}},
+			{"kind":"decision","score":0.8,"payload":{"id":"adr-1"}},
+			{"kind":"change","score":0.2,"payload":{"id":"c-1"}}]}`)
+	}))
+	defer srv.Close()
+
+	resp, err := NewClient(srv.URL).Search(context.Background(), SearchRequest{
+		Query: "q",
+		Kinds: []Kind{KindIssue, KindDecision},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Hits) != 2 {
+		t.Fatalf("hits = %+v", resp.Hits)
+	}
+	if issue, ok := resp.Hits[0].Payload.(*Issue); !ok || issue.RootCause != "rc" {
+		t.Errorf("hit 0 payload = %#v", resp.Hits[0].Payload)
+	}
+	if _, ok := resp.Hits[1].Payload.(*Decision); !ok {
+		t.Errorf("hit 1 payload = %#v", resp.Hits[1].Payload)
+	}
+}
@@ -0,0 +1,51 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Severity is the impact level of a failure. It always serializes as its
+// canonical lowercase name.
+type Severity string
+
+const (
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// ParseSeverity accepts a severity name in any case.
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(strings.ToLower(strings.TrimSpace(s)))
+	if !sev.Valid() {
+		return "", fmt.Errorf("unknown severity %q", s)
+	}
+	return sev, nil
+}
+
+// Valid reports whether s is one of the defined severities.
+func (s Severity) Valid() bool {
+	switch Severity(strings.ToLower(string(s))) {
+	case SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical:
+		return true
+	}
+	return false
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToLower(string(s)))
+}
+
+// UnmarshalJSON keeps unknown values rather than failing, so a server that
+// adds a level doesn't break decoding.
+func (s *Severity) UnmarshalJSON(b []byte) error {
+	var raw string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*s = Severity(strings.ToLower(raw))
+	return nil
+}
@@ -0,0 +1,35 @@
+package context
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want Severity
+		ok   bool
+	}{
+		{"high", SeverityHigh, true},
+		{" Critical ", SeverityCritical, true},
+		{"LOW", SeverityLow, true},
+		{"sev1", "", false},
+		{"", "", false},
+	} {
+		got, err := ParseSeverity(tt.in)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("ParseSeverity(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+
+	// The wire form is lower case, and unknown levels survive decoding.
+	b, _ := json.Marshal(Severity("HIGH"))
+	if string(b) != `"high"` {
+		t.Errorf("marshal = %s", b)
+	}
+	var s Severity
+	if err := json.Unmarshal([]byte(`"Sev0"`), &s); err != nil || s != "sev0" || s.Valid() {
+		t.Errorf("unmarshal = %q, %v", s, err)
+	}
+}
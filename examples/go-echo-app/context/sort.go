@@ -0,0 +1,45 @@
+package context
+
+import "sort"
+
+// SortBy selects the field Query orders KeyDecisions by.
+type SortBy string
+
+const (
+	SortByScore     SortBy = "score"
+	SortByCreatedAt SortBy = "created_at"
+)
+
+// Order is the direction of a sort. The zero value means OrderDesc: highest
+// score or most recent first.
+type Order string
+
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+func (s SortBy) valid() bool { return s == SortByScore || s == SortByCreatedAt }
+
+func (o Order) valid() bool { return o == OrderAsc || o == OrderDesc }
+
+// SortDecisions stably sorts decisions in place. Query already applies this
+// when SortBy is set, for servers that ignore the sort parameters.
+func SortDecisions(decisions []Decision, by SortBy, order Order) {
+	var less func(a, b *Decision) bool
+	switch by {
+	case SortByScore:
+		less = func(a, b *Decision) bool { return a.Score < b.Score }
+	case SortByCreatedAt:
+		less = func(a, b *Decision) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	default:
+		return
+	}
+	if order != OrderAsc {
+		asc := less
+		less = func(a, b *Decision) bool { return asc(b, a) }
+	}
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return less(&decisions[i], &decisions[j])
+	})
+}
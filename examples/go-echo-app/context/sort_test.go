@@ -0,0 +1,45 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestQuerySortsWhenServerIgnoresParams(t *testing.T) {
+	var rawQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		fmt.Fprint(w, `{"key_decisions":[
+			{"id":"old","created_at":"2024-01-01T00:00:00Z"},
+			{"id":"new","created_at":"2024-03-01T00:00:00Z"},
+			{"id":"mid","created_at":"2024-02-01T00:00:00Z"}]}`)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	resp, err := c.Query(context.Background(), QueryRequest{Query: "q", SortBy: SortByCreatedAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rawQuery != "order=desc&sort=created_at" {
+		t.Errorf("query = %q", rawQuery)
+	}
+	var ids []string
+	for _, d := range resp.KeyDecisions {
+		ids = append(ids, d.ID)
+	}
+	if got := strings.Join(ids, ","); got != "new,mid,old" {
+		t.Errorf("order = %s", got)
+	}
+
+	_, err = c.Query(context.Background(), QueryRequest{Query: "q", Order: OrderAsc})
+	var ve *ValidationError
+	if !errors.As(err, &ve) || ve.Fields[0].Field != "order" {
+		t.Errorf("err = %v, want order validation error", err)
+	}
+}
@@ -0,0 +1,58 @@
+package context
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// StalenessReport summarizes how many decisions have gone unreviewed for
+// longer than a threshold.
+type StalenessReport struct {
+	Threshold   time.Duration
+	GeneratedAt time.Time
+	Total       int
+	// StaleIDs are decisions last reviewed, or created if never reviewed,
+	// more than Threshold before GeneratedAt, oldest first.
+	StaleIDs []string
+	// UndatedIDs have neither timestamp, so their age is unknown. They are
+	// not counted as stale.
+	UndatedIDs []string
+}
+
+// Stale returns len(r.StaleIDs).
+func (r *StalenessReport) Stale() int { return len(r.StaleIDs) }
+
+// LastReviewed returns ReviewedAt, falling back to CreatedAt for decisions
+// that have never been reviewed.
+func (d Decision) LastReviewed() time.Time {
+	if !d.ReviewedAt.IsZero() {
+		return d.ReviewedAt
+	}
+	return d.CreatedAt
+}
+
+// StalenessReport lists every ADR and reports those not reviewed within
+// threshold.
+func (c *Client) StalenessReport(ctx context.Context, threshold time.Duration, opts ...CallOption) (*StalenessReport, error) {
+	adrs, err := c.ListADRs(ctx, ADRFilter{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now().UTC()
+	r := &StalenessReport{Threshold: threshold, GeneratedAt: now, Total: len(adrs)}
+	sort.SliceStable(adrs, func(i, j int) bool {
+		return adrs[i].LastReviewed().Before(adrs[j].LastReviewed())
+	})
+	for _, d := range adrs {
+		last := d.LastReviewed()
+		switch {
+		case last.IsZero():
+			r.UndatedIDs = append(r.UndatedIDs, d.ID)
+		case now.Sub(last) > threshold:
+			r.StaleIDs = append(r.StaleIDs, d.ID)
+		}
+	}
+	return r, nil
+}
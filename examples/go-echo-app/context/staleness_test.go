@@ -0,0 +1,42 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStalenessReport(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	threshold := 90 * 24 * time.Hour
+	adrs := []Decision{
+		{ID: "at-cutoff", ReviewedAt: now.Add(-threshold)},
+		{ID: "reviewed-old", ReviewedAt: now.Add(-threshold - time.Second), CreatedAt: now},
+		{ID: "fresh", ReviewedAt: now.Add(-time.Hour)},
+		{ID: "created-old", CreatedAt: now.Add(-2 * threshold)},
+		{ID: "undated"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(adrs)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+	c.now = func() time.Time { return now }
+
+	r, err := c.StalenessReport(context.Background(), threshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Exactly threshold old is not yet stale; never-reviewed ones age from
+	// creation.
+	if !slices.Equal(r.StaleIDs, []string{"created-old", "reviewed-old"}) {
+		t.Errorf("stale = %q", r.StaleIDs)
+	}
+	if !slices.Equal(r.UndatedIDs, []string{"undated"}) || r.Total != 5 || !r.GeneratedAt.Equal(now) {
+		t.Errorf("report = %+v", r)
+	}
+}
@@ -0,0 +1,62 @@
+package context
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Each latency sample moves the average 1/2^latencyShift of the way towards
+// it: 1/8, as in TCP's smoothed RTT.
+const latencyShift = 3
+
+// ClientStats is a snapshot of the counters kept by every Client.
+type ClientStats struct {
+	Requests int64 // calls completed, including failures
+	Errors   int64 // calls that returned an error
+	// AvgLatency is an exponentially weighted moving average of call
+	// duration, retries included.
+	AvgLatency time.Duration
+}
+
+type stats struct {
+	requests   atomic.Int64
+	errors     atomic.Int64
+	avgLatency atomic.Int64 // nanoseconds
+	lastStatus atomic.Int64
+}
+
+func (s *stats) observe(status int, d time.Duration, err error) {
+	if s.requests.Add(1) == 1 {
+		s.avgLatency.Store(int64(d))
+	} else {
+		for {
+			old := s.avgLatency.Load()
+			next := old + (int64(d)-old)>>latencyShift
+			if s.avgLatency.CompareAndSwap(old, next) {
+				break
+			}
+		}
+	}
+	if err != nil {
+		s.errors.Add(1)
+	}
+	if status != 0 {
+		s.lastStatus.Store(int64(status))
+	}
+}
+
+// Stats returns the client's request counters. It is cheap enough to call
+// on every request.
+func (c *Client) Stats() ClientStats {
+	return ClientStats{
+		Requests:   c.stats.requests.Load(),
+		Errors:     c.stats.errors.Load(),
+		AvgLatency: time.Duration(c.stats.avgLatency.Load()),
+	}
+}
+
+// LastStatus returns the HTTP status of the most recent response, or 0 if
+// none has been received.
+func (c *Client) LastStatus() int {
+	return int(c.stats.lastStatus.Load())
+}
@@ -0,0 +1,56 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	var status atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		w.Write([]byte(`{"domains":[]}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+	if got := c.LastStatus(); got != 0 {
+		t.Errorf("initial LastStatus = %d", got)
+	}
+
+	status.Store(http.StatusOK)
+	if _, err := c.ListDomains(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if s := c.Stats(); s.Requests != 1 || s.Errors != 0 || s.AvgLatency <= 0 {
+		t.Errorf("after 200: %+v", s)
+	}
+	if got := c.LastStatus(); got != http.StatusOK {
+		t.Errorf("LastStatus = %d, want 200", got)
+	}
+
+	status.Store(http.StatusInternalServerError)
+	if _, err := c.ListDomains(context.Background()); err == nil {
+		t.Fatal("500: no error")
+	}
+	if s := c.Stats(); s.Requests != 2 || s.Errors != 1 {
+		t.Errorf("after 500: %+v", s)
+	}
+	if got := c.LastStatus(); got != http.StatusInternalServerError {
+		t.Errorf("LastStatus = %d, want 500", got)
+	}
+
+	// A transport error counts but leaves the last status alone.
+	srv.Close()
+	if _, err := c.ListDomains(context.Background()); err == nil {
+		t.Fatal("closed server: no error")
+	}
+	if s := c.Stats(); s.Requests != 3 || s.Errors != 2 {
+		t.Errorf("after transport error: %+v", s)
+	}
+	if got := c.LastStatus(); got != http.StatusInternalServerError {
+		t.Errorf("LastStatus = %d after transport error, want 500", got)
+	}
+}
@@ -0,0 +1,156 @@
+package context
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	streamRetryBase     = time.Second
+	streamRetryMax      = 30 * time.Second
+	streamMaxReconnects = 10
+)
+
+// StreamChanges tails /changes/stream as Server-Sent Events. Each event's
+// data is decoded into a Change. Dropped connections are resumed with the
+// Last-Event-ID header so no events are missed; the error channel receives
+// at most one value, when the stream gives up. Cancelling ctx closes both
+// channels and the connection.
+func (c *Client) StreamChanges(ctx context.Context, filter ChangeFilter) (<-chan Change, <-chan error) {
+	changes := make(chan Change)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(changes)
+
+		lastID := ""
+		delay := streamRetryBase
+		failures := 0
+		for {
+			n, err := c.streamChangesOnce(ctx, filter, &lastID, &delay, changes)
+			if ctx.Err() != nil {
+				return
+			}
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests {
+				errc <- err
+				return
+			}
+			if n > 0 {
+				failures = 0
+			}
+			failures++
+			if failures > streamMaxReconnects {
+				errc <- fmt.Errorf("stream changes: giving up after %d reconnects: %w", streamMaxReconnects, err)
+				return
+			}
+			if sleep(ctx, min(delay<<(failures-1), streamRetryMax)) != nil {
+				return
+			}
+		}
+	}()
+
+	return changes, errc
+}
+
+// streamChangesOnce reads one connection until it ends and returns how many
+// changes were delivered. lastID and delay are updated from the stream's id
+// and retry fields.
+func (c *Client) streamChangesOnce(ctx context.Context, filter ChangeFilter, lastID *string, delay *time.Duration, out chan<- Change) (int, error) {
+	req, err := c.newRequest(ctx, call{
+		op:     "StreamChanges",
+		method: http.MethodGet,
+		path:   "/changes/stream",
+		query:  filter.values(),
+
+		requestID: c.requestID(),
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if *lastID != "" {
+		req.Header.Set("Last-Event-ID", *lastID)
+	}
+
+	// The feed is long-lived, so the client-wide timeout must not apply.
+	hc := *c.client
+	hc.Timeout = 0
+	resp, err := hc.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("http get: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := gunzipBody(resp); err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, c.newAPIError(resp)
+	}
+
+	var (
+		n     int
+		id    string
+		event string
+		data  strings.Builder
+	)
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			if data.Len() > 0 && (event == "" || event == "change") {
+				var ch Change
+				if err := json.Unmarshal([]byte(data.String()), &ch); err != nil {
+					return n, fmt.Errorf("decode change event: %w", err)
+				}
+				select {
+				case out <- ch:
+					n++
+				case <-ctx.Done():
+					return n, ctx.Err()
+				}
+			}
+			if id != "" {
+				*lastID = id
+			}
+			id, event = "", ""
+			data.Reset()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			id = value
+		case "event":
+			event = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				*delay = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return n, fmt.Errorf("read change stream: %w", err)
+	}
+	return n, errors.New("change stream closed by server")
+}
@@ -0,0 +1,85 @@
+package context
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStreamChangesResumesWithLastEventID(t *testing.T) {
+	conns := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conns++
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch conns {
+		case 1:
+			fmt.Fprint(w, "retry: 1\n\nid: 1\ndata: {\"id\":\"c1\"}\n\nid: 2\ndata: {\"id\":\"c2\"}\n\n")
+		default:
+			if got := r.Header.Get("Last-Event-ID"); got != "2" {
+				t.Errorf("Last-Event-ID = %q, want 2", got)
+			}
+			fmt.Fprint(w, "id: 3\ndata: {\"id\":\"c3\"}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, errc := NewClient(srv.URL).StreamChanges(ctx, ChangeFilter{})
+
+	var ids []string
+	for ch := range changes {
+		ids = append(ids, ch.ID)
+		if len(ids) == 3 {
+			cancel()
+		}
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("err = %v", err)
+	}
+	if strings.Join(ids, ",") != "c1,c2,c3" {
+		t.Errorf("ids = %v", ids)
+	}
+}
+
+func TestStreamChangesGzip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Accept-Encoding = %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		// Padding so deflate emits compressed rather than stored blocks.
+		fmt.Fprint(zw, ": "+strings.Repeat("keepalive ", 100)+"\n\n")
+		fmt.Fprint(zw, "id: 1\ndata: {\"id\":\"c1\"}\n\nid: 2\ndata: {\"id\":\"c2\"}\n\n")
+		zw.Flush()
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, errc := NewClient(srv.URL).StreamChanges(ctx, ChangeFilter{})
+
+	var ids []string
+	for ch := range changes {
+		ids = append(ids, ch.ID)
+		if len(ids) == 2 {
+			cancel()
+		}
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("err = %v", err)
+	}
+	if strings.Join(ids, ",") != "c1,c2" {
+		t.Errorf("ids = %v", ids)
+	}
+}
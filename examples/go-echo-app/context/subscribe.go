@@ -0,0 +1,168 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNoWebSocket is returned by SubscribeQuery on a client built without
+// WithWebSocket.
+var ErrNoWebSocket = errors.New("subscribe query: no WebSocket dialer configured")
+
+// A WebSocketConn is one open WebSocket, as returned by a WebSocketDialer.
+// Implementations keep the connection alive themselves: ReadMessage must
+// fail once the peer stops answering pings. Close may be called while
+// ReadMessage is blocked, and must unblock it.
+type WebSocketConn interface {
+	ReadMessage() ([]byte, error)
+	WriteMessage(data []byte) error
+	Close() error
+}
+
+// A WebSocketDialer opens WebSockets for SubscribeQuery. It lives outside
+// this package so the client doesn't depend on a WebSocket library;
+// contextws.Dialer is one. A failed handshake should be returned as an
+// *APIError so SubscribeQuery can tell it from a network failure; its
+// RawBody is passed through the client's redactor before the caller sees
+// it.
+type WebSocketDialer interface {
+	Dial(ctx context.Context, url string, header http.Header) (WebSocketConn, error)
+}
+
+// WithWebSocket sets the dialer SubscribeQuery connects with.
+func WithWebSocket(d WebSocketDialer) Option {
+	return func(c *Client) {
+		c.wsDialer = d
+	}
+}
+
+// SubscribeQuery subscribes to req over a WebSocket to /ws/query and sends
+// each decision the server pushes as it is recorded. The request is sent as
+// the first message on every connection, so a dropped socket is redialled
+// with backoff and the subscription replayed; the server may resend matches
+// it already pushed. The error channel receives at most one value, when the
+// subscription gives up. Cancelling ctx closes the socket and both channels.
+func (c *Client) SubscribeQuery(ctx context.Context, req QueryRequest, opts ...CallOption) (<-chan Decision, <-chan error) {
+	decisions := make(chan Decision)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(decisions)
+		if err := c.subscribeQuery(ctx, req, opts, decisions); err != nil && ctx.Err() == nil {
+			errc <- err
+		}
+	}()
+
+	return decisions, errc
+}
+
+func (c *Client) subscribeQuery(ctx context.Context, req QueryRequest, opts []CallOption, out chan<- Decision) error {
+	if c.wsDialer == nil {
+		return ErrNoWebSocket
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	if c.validateDomains {
+		if err := c.ValidateDomains(req.Domains); err != nil {
+			return err
+		}
+	}
+	body, err := c.embed(ctx, req)
+	if err != nil {
+		return err
+	}
+	sub, err := json.Marshal(body)
+	if err != nil {
+		return newMarshalError("SubscribeQuery", body, err)
+	}
+
+	// Reconnects back off like StreamChanges.
+	failures := 0
+	for {
+		n, err := c.subscribeOnce(ctx, req, sub, opts, out)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests {
+			return err
+		}
+		if n > 0 {
+			failures = 0
+		}
+		failures++
+		if failures > streamMaxReconnects {
+			return fmt.Errorf("subscribe query: giving up after %d reconnects: %w", streamMaxReconnects, err)
+		}
+		if err := sleep(ctx, min(streamRetryBase<<(failures-1), streamRetryMax)); err != nil {
+			return err
+		}
+	}
+}
+
+// subscribeOnce reads one connection until it fails and returns how many
+// decisions were delivered.
+func (c *Client) subscribeOnce(ctx context.Context, req QueryRequest, sub []byte, opts []CallOption, out chan<- Decision) (int, error) {
+	hr, err := c.newRequest(ctx, call{
+		op:     "SubscribeQuery",
+		method: http.MethodGet,
+		path:   "/ws/query",
+		query:  req.values(),
+		opts:   opts,
+
+		requestID: c.requestID(),
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	hr.Header.Del("Accept")
+	hr.Header.Del("Accept-Encoding")
+	u := *hr.URL
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+
+	conn, err := c.wsDialer.Dial(ctx, u.String(), hr.Header)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			// The dialer has no redactor; apply the client's, as
+			// newAPIError does.
+			apiErr.RawBody = c.redact(apiErr.RawBody)
+			if apiErr.RequestID == "" {
+				apiErr.RequestID = hr.Header.Get("X-Request-ID")
+			}
+		}
+		return 0, fmt.Errorf("dial websocket: %w", err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := conn.WriteMessage(sub); err != nil {
+		return 0, fmt.Errorf("send subscription: %w", err)
+	}
+	for n := 0; ; {
+		msg, err := conn.ReadMessage()
+		if err != nil {
+			return n, fmt.Errorf("read subscription: %w", err)
+		}
+		var d Decision
+		if err := json.Unmarshal(msg, &d); err != nil {
+			return n, fmt.Errorf("decode decision: %w", err)
+		}
+		select {
+		case out <- d:
+			n++
+		case <-ctx.Done():
+			return n, ctx.Err()
+		}
+	}
+}
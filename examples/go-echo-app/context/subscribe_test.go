@@ -0,0 +1,124 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakeWSConn struct {
+	msgs   chan []byte // closed to drop the connection
+	sent   chan []byte
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newFakeWSConn(msgs ...string) *fakeWSConn {
+	c := &fakeWSConn{msgs: make(chan []byte, len(msgs)), sent: make(chan []byte, 1), closed: make(chan struct{})}
+	for _, m := range msgs {
+		c.msgs <- []byte(m)
+	}
+	return c
+}
+
+func (c *fakeWSConn) ReadMessage() ([]byte, error) {
+	select {
+	case m, ok := <-c.msgs:
+		if !ok {
+			return nil, io.EOF
+		}
+		return m, nil
+	case <-c.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (c *fakeWSConn) WriteMessage(data []byte) error {
+	c.sent <- data
+	return nil
+}
+
+func (c *fakeWSConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+type fakeWSDialer struct {
+	mu    sync.Mutex
+	urls  []string
+	conns []*fakeWSConn
+}
+
+func (d *fakeWSDialer) Dial(ctx context.Context, url string, header http.Header) (WebSocketConn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.urls = append(d.urls, url)
+	if len(d.conns) == 0 {
+		return nil, &APIError{StatusCode: http.StatusForbidden, RawBody: `{"error":"bad token","token":"s3cret"}`}
+	}
+	c := d.conns[0]
+	d.conns = d.conns[1:]
+	return c, nil
+}
+
+func TestSubscribeQuery(t *testing.T) {
+	first := newFakeWSConn(`{"id":"a","title":"A"}`)
+	close(first.msgs)
+	second := newFakeWSConn(`{"id":"b","title":"B"}`)
+	d := &fakeWSDialer{conns: []*fakeWSConn{first, second}}
+	c := NewClient("https://ctx.example/api", WithWebSocket(d))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	decisions, errc := c.SubscribeQuery(ctx, QueryRequest{Query: "routing"})
+	for _, want := range []string{"a", "b"} {
+		if got := <-decisions; got.ID != want {
+			t.Fatalf("decision = %+v, want %s", got, want)
+		}
+	}
+	for i, conn := range []*fakeWSConn{first, second} {
+		var req QueryRequest
+		if err := json.Unmarshal(<-conn.sent, &req); err != nil || req.Query != "routing" {
+			t.Errorf("conn %d: subscription = %+v, %v", i, req, err)
+		}
+	}
+
+	cancel()
+	if _, ok := <-decisions; ok {
+		t.Error("decisions not closed")
+	}
+	if err, ok := <-errc; ok {
+		t.Errorf("err after cancel = %v", err)
+	}
+	select {
+	case <-second.closed:
+	default:
+		t.Error("socket left open after cancel")
+	}
+	if len(d.urls) != 2 || !strings.HasPrefix(d.urls[0], "wss://ctx.example/api/ws/query") {
+		t.Errorf("dialed %q", d.urls)
+	}
+}
+
+func TestSubscribeQueryErrors(t *testing.T) {
+	_, errc := NewClient("http://ctx.example").SubscribeQuery(context.Background(), QueryRequest{Query: "q"})
+	if err := <-errc; !errors.Is(err, ErrNoWebSocket) {
+		t.Errorf("no dialer: err = %v", err)
+	}
+
+	// A refused handshake is not retried.
+	d := &fakeWSDialer{}
+	_, errc = NewClient("http://ctx.example", WithWebSocket(d)).SubscribeQuery(context.Background(), QueryRequest{Query: "q"})
+	var apiErr *APIError
+	if err := <-errc; !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || len(d.urls) != 1 {
+		t.Errorf("refused: err = %v after %d dials", err, len(d.urls))
+	} else if strings.Contains(apiErr.RawBody, "s3cret") || apiErr.RequestID == "" {
+		t.Errorf("refused: RawBody %q not redacted or request ID %q missing", apiErr.RawBody, apiErr.RequestID)
+	}
+}
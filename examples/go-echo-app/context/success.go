@@ -0,0 +1,42 @@
+package context
+
+import (
+	"context"
+	"net/http"
+)
+
+// SuccessRequest records a mitigation or pattern that worked, the
+// counterpart to FailureRequest.
+type SuccessRequest struct {
+	Title    string `json:"title"`
+	Approach string `json:"approach"`
+	Outcome  string `json:"outcome"`
+	// Metrics are the measurements backing the outcome, e.g.
+	// {"p99_latency": "-40%"}.
+	Metrics map[string]string `json:"metrics,omitempty"`
+	Tags    []string          `json:"tags,omitempty"`
+}
+
+// RecordSuccess stores req and returns its ID.
+func (c *Client) RecordSuccess(ctx context.Context, req SuccessRequest, opts ...CallOption) (string, error) {
+	if err := req.Validate(); err != nil {
+		return "", err
+	}
+	var resp struct {
+		ID string `json:"id"`
+	}
+	err := c.write(ctx, call{
+		op:     "RecordSuccess",
+		method: http.MethodPost,
+		path:   "/success",
+		in:     req,
+		out:    &resp,
+		opts:   opts,
+		keyed:  true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
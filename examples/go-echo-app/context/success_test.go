@@ -0,0 +1,45 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRecordSuccess(t *testing.T) {
+	var got SuccessRequest
+	var key string
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodPost || r.URL.Path != "/success" {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		key = r.Header.Get("Idempotency-Key")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"id":"s-1"}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	req := SuccessRequest{
+		Title: "Pool sizing", Approach: "raise max conns", Outcome: "no timeouts",
+		Metrics: map[string]string{"p99_latency": "-40%"}, Tags: []string{"db"},
+	}
+	id, err := c.RecordSuccess(context.Background(), req)
+	if err != nil || id != "s-1" {
+		t.Fatalf("RecordSuccess = %q, %v", id, err)
+	}
+	if !reflect.DeepEqual(got, req) || key == "" {
+		t.Errorf("sent %+v with key %q", got, key)
+	}
+
+	var ve *ValidationError
+	if _, err := c.RecordSuccess(context.Background(), SuccessRequest{Title: "t"}); !errors.As(err, &ve) || requests != 1 {
+		t.Errorf("invalid: err = %v after %d requests", err, requests)
+	}
+}
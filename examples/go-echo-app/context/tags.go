@@ -0,0 +1,84 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// NormalizeTag trims tag and lower-cases it, so "REST-API " and "rest-api"
+// are the same tag. Pass it to WithTagNormalizer.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// WithTagNormalizer rewrites every tag the client sends with fn: on ADRs,
+// failures and changes it creates, and in the Tags of list filters so they
+// match what was stored. Tags fn maps to "" are dropped. Without it tags
+// are sent as given, except that empty and repeated tags within a record
+// are always dropped.
+func WithTagNormalizer(fn func(string) string) Option {
+	return func(c *Client) {
+		c.tagNormalizer = fn
+	}
+}
+
+// tags returns a normalized copy of tags without empty or repeated
+// entries, keeping the first of each.
+func (c *Client) tags(tags []string) []string {
+	if len(tags) == 0 {
+		return tags
+	}
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		if c.tagNormalizer != nil {
+			t = c.tagNormalizer(t)
+		}
+		if t != "" && !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// TagCount is a tag and the number of records using it.
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// ListTags returns the tags starting with prefix, most used first and then
+// by name. An empty prefix lists every tag.
+func (c *Client) ListTags(ctx context.Context, prefix string, opts ...CallOption) ([]TagCount, error) {
+	var q url.Values
+	if prefix != "" {
+		q = url.Values{"prefix": {prefix}}
+	}
+	var resp struct {
+		Tags []TagCount `json:"tags"`
+	}
+	err := c.do(ctx, call{
+		op:     "ListTags",
+		method: http.MethodGet,
+		path:   "/tags",
+		query:  q,
+		out:    &resp,
+		opts:   opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(resp.Tags, func(i, j int) bool {
+		a, b := resp.Tags[i], resp.Tags[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+	return resp.Tags, nil
+}
@@ -0,0 +1,94 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTagNormalization(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			got = append(got, r.URL.Query().Get("tags"))
+			w.Write([]byte("[]"))
+			return
+		}
+		var body struct {
+			Tags []string `json:"tags"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		got = append(got, strings.Join(body.Tags, ","))
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	tags := []string{"REST-API", " rest-api ", "Go", "", "Go"}
+	send := func(c *Client) {
+		t.Helper()
+		ctx := context.Background()
+		got = nil
+		if err := c.CreateADR(ctx, ADRRequest{Title: "t", Decision: "d", Tags: tags}); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.RecordFailure(ctx, FailureRequest{Title: "t", RootCause: "rc", Severity: SeverityLow, Tags: tags}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.CreateChange(ctx, ChangeRequest{Type: ChangeFix, Title: "t", Tags: tags}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.ListADRs(ctx, ADRFilter{Tags: tags}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	send(NewClient(srv.URL))
+	for i, g := range got {
+		if g != "REST-API, rest-api ,Go" {
+			t.Errorf("default, request %d: tags = %q", i, g)
+		}
+	}
+	send(NewClient(srv.URL, WithTagNormalizer(NormalizeTag)))
+	for i, g := range got {
+		if g != "rest-api,go" {
+			t.Errorf("normalized, request %d: tags = %q", i, g)
+		}
+	}
+	if tags[0] != "REST-API" {
+		t.Error("caller's tags were modified")
+	}
+}
+
+func TestListTags(t *testing.T) {
+	var queries []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tags" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		queries = append(queries, r.URL.Query())
+		w.Write([]byte(`{"tags":[{"name":"rest_api","count":2},{"name":"rest-api","count":9},{"name":"rest","count":2}]}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	tags, err := c.ListTags(context.Background(), "rest&")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Most used first, then by name.
+	want := []TagCount{{"rest-api", 9}, {"rest", 2}, {"rest_api", 2}}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %+v", tags)
+	}
+	if _, err := c.ListTags(context.Background(), ""); err != nil {
+		t.Fatal(err)
+	}
+	if queries[0].Get("prefix") != "rest&" || len(queries[0]) != 1 || len(queries[1]) != 0 {
+		t.Errorf("queries = %v", queries)
+	}
+}
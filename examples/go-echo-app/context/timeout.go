@@ -0,0 +1,60 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// WithCallTimeout bounds a single call, retries included, to d instead of
+// the client's 10s default, so it can lengthen the limit as well as shorten
+// it. It derives a deadline from the call's context, so an earlier deadline
+// already on the context still wins.
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// TimeoutError is returned when a call runs out of time, whether the
+// context's deadline passed or the transport gave up on the server. It
+// matches context.DeadlineExceeded with errors.Is, and the underlying
+// error with errors.As.
+type TimeoutError struct {
+	Method  string        // client method, e.g. "Query"
+	Elapsed time.Duration // since the call started, retries included
+	URL     string
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s %s: timed out after %v: %v", e.Method, e.URL, e.Elapsed.Round(time.Millisecond), e.Err)
+}
+
+func (e *TimeoutError) Unwrap() []error { return []error{context.DeadlineExceeded, e.Err} }
+
+// isTimeout reports whether err is a deadline or transport timeout. A
+// caller cancelling the context is not one.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// timeoutError wraps err in a *TimeoutError if it is a timeout.
+func (c *Client) timeoutError(cl call, req *http.Request, start time.Time, err error) error {
+	var te *TimeoutError
+	if !isTimeout(err) || errors.As(err, &te) {
+		return err
+	}
+	u := cl.path
+	if req != nil {
+		u = req.URL.String()
+	}
+	return &TimeoutError{Method: cl.op, Elapsed: time.Since(start), URL: c.redact(u), Err: err}
+}
@@ -0,0 +1,90 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCallTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	start := time.Now()
+	_, err := NewClient(srv.URL).Query(context.Background(), QueryRequest{Query: "q"}, WithCallTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("took %v", elapsed)
+	}
+}
+
+func TestCallTimeoutExtendsDefault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+	c.timeout = 30 * time.Millisecond // stands in for the 10s default
+
+	if _, err := c.Query(context.Background(), QueryRequest{Query: "q"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("default: err = %v, want deadline exceeded", err)
+	}
+	if _, err := c.Query(context.Background(), QueryRequest{Query: "q"}, WithCallTimeout(time.Second)); err != nil {
+		t.Errorf("WithCallTimeout(1s): %v", err)
+	}
+}
+
+func TestTimeoutError(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	check := func(name string, err error) {
+		t.Helper()
+		var te *TimeoutError
+		if !errors.As(err, &te) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("%s: err = %v, want a TimeoutError", name, err)
+		}
+		if te.Method != "Query" || te.URL != srv.URL+"/context/query" || te.Elapsed < 20*time.Millisecond {
+			t.Errorf("%s: %+v", name, te)
+		}
+	}
+
+	// The context's deadline passes.
+	_, err := NewClient(srv.URL).Query(context.Background(), QueryRequest{Query: "q"}, WithCallTimeout(20*time.Millisecond))
+	check("context", err)
+
+	// The transport gives up first.
+	hc := &http.Client{Timeout: 20 * time.Millisecond}
+	_, err = NewClient(srv.URL, WithHTTPClient(hc)).Query(context.Background(), QueryRequest{Query: "q"})
+	check("transport", err)
+
+	// Cancelling is not a timeout.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err = NewClient(srv.URL).Query(ctx, QueryRequest{Query: "q"})
+	var te *TimeoutError
+	if !errors.Is(err, context.Canceled) || errors.As(err, &te) {
+		t.Errorf("cancelled: err = %v", err)
+	}
+}
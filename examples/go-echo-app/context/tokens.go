@@ -0,0 +1,102 @@
+package context
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// charsPerToken is the usual rough ratio for English text and JSON.
+const charsPerToken = 4
+
+// WithClientTokenBudget makes Query enforce QueryRequest.MaxTokens itself
+// when the server returns more: the lowest-scored decisions, issues and
+// changes are dropped until EstimatedTokens fits. Off by default.
+func WithClientTokenBudget(enabled bool) Option {
+	return func(c *Client) {
+		c.tokenBudget = enabled
+	}
+}
+
+func estimateTokens(v any) int {
+	b, _ := json.Marshal(v)
+	return (len(b) + charsPerToken - 1) / charsPerToken
+}
+
+// EstimatedTokens approximates the tokens r's items take up when serialized
+// into a prompt, at about four characters per token.
+func (r *QueryResponse) EstimatedTokens() int {
+	n := 0
+	for _, d := range r.KeyDecisions {
+		n += estimateTokens(d)
+	}
+	for _, i := range r.KnownIssues {
+		n += estimateTokens(i)
+	}
+	for _, c := range r.RecentChanges {
+		n += estimateTokens(c)
+	}
+	return n
+}
+
+// truncateTokens drops the lowest-scored items until r fits in max tokens.
+// Order within each slice is preserved.
+func (r *QueryResponse) truncateTokens(max int) {
+	type item struct {
+		kind   Kind
+		index  int
+		score  float64
+		tokens int
+	}
+	var items []item
+	total := 0
+	add := func(kind Kind, i int, score float64, v any) {
+		t := estimateTokens(v)
+		items = append(items, item{kind, i, score, t})
+		total += t
+	}
+	for i, d := range r.KeyDecisions {
+		add(KindDecision, i, d.Score, d)
+	}
+	for i, is := range r.KnownIssues {
+		add(KindIssue, i, is.Score, is)
+	}
+	for i, c := range r.RecentChanges {
+		add(KindChange, i, c.Score, c)
+	}
+	if total <= max {
+		return
+	}
+
+	// Lowest score first; among equals, drop the later item.
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].score != items[j].score {
+			return items[i].score < items[j].score
+		}
+		return items[i].index > items[j].index
+	})
+	drop := map[Kind]map[int]bool{KindDecision: {}, KindIssue: {}, KindChange: {}}
+	for _, it := range items {
+		if total <= max {
+			break
+		}
+		drop[it.kind][it.index] = true
+		total -= it.tokens
+	}
+
+	r.KeyDecisions = keep(r.KeyDecisions, drop[KindDecision])
+	r.KnownIssues = keep(r.KnownIssues, drop[KindIssue])
+	r.RecentChanges = keep(r.RecentChanges, drop[KindChange])
+}
+
+func keep[T any](s []T, drop map[int]bool) []T {
+	if len(drop) == 0 {
+		return s
+	}
+	out := s[:0]
+	for i, v := range s {
+		if !drop[i] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,35 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientTokenBudgetDropsLowestScored(t *testing.T) {
+	big := strings.Repeat("x", 400) // ~100 tokens each
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(QueryResponse{
+			KeyDecisions:  []Decision{{ID: "d-hi", Title: big, Score: 0.9}, {ID: "d-lo", Title: big, Score: 0.1}},
+			KnownIssues:   []Issue{{ID: "i-mid", Title: big, Score: 0.5}},
+			RecentChanges: []Change{{ID: "c-lo", Title: big, Score: 0.2}},
+		})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithClientTokenBudget(true))
+	resp, err := c.Query(context.Background(), QueryRequest{Query: "q", MaxTokens: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := resp.EstimatedTokens(); n > 300 {
+		t.Errorf("EstimatedTokens = %d, want <= 300", n)
+	}
+	if len(resp.KeyDecisions) != 1 || resp.KeyDecisions[0].ID != "d-hi" ||
+		len(resp.KnownIssues) != 1 || len(resp.RecentChanges) != 0 {
+		t.Errorf("kept %+v %+v %+v", resp.KeyDecisions, resp.KnownIssues, resp.RecentChanges)
+	}
+}
@@ -0,0 +1,188 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+// Tx buffers creates for Transaction. Its methods validate immediately but
+// send nothing until the transaction function returns.
+type Tx struct {
+	c   *Client
+	ops []txOp
+}
+
+type txOp struct {
+	Kind Kind `json:"kind"`
+	Data any  `json:"data"`
+}
+
+func (tx *Tx) CreateADR(req ADRRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	if req.Visibility == "" {
+		req.Visibility = tx.c.visibility
+	}
+	req.Tags = tx.c.tags(req.Tags)
+	tx.ops = append(tx.ops, txOp{Kind: KindDecision, Data: req.payload()})
+	return nil
+}
+
+func (tx *Tx) RecordFailure(req FailureRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	req.Tags = tx.c.tags(req.Tags)
+	tx.ops = append(tx.ops, txOp{Kind: KindIssue, Data: req})
+	return nil
+}
+
+func (tx *Tx) CreateChange(req ChangeRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	req.Tags = tx.c.tags(req.Tags)
+	tx.ops = append(tx.ops, txOp{Kind: KindChange, Data: req})
+	return nil
+}
+
+// Transaction runs fn and then commits everything it buffered on tx in one
+// POST /batch/transaction, which the server applies all-or-nothing. If fn
+// returns an error nothing is sent.
+//
+// Servers without that endpoint get a best-effort emulation: records are
+// created one at a time and, if one fails, those already created are
+// deleted in reverse order. That rollback is not atomic. Other clients may
+// observe the partial state, and if a delete also fails the record is left
+// behind; the returned error then includes the rollback failure.
+func (c *Client) Transaction(ctx context.Context, fn func(tx *Tx) error, opts ...CallOption) error {
+	tx := &Tx{c: c}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if len(tx.ops) == 0 {
+		return nil
+	}
+
+	err := c.do(ctx, call{
+		op:     "Transaction",
+		method: http.MethodPost,
+		path:   "/batch/transaction",
+		in:     map[string]any{"operations": tx.ops},
+		opts:   opts,
+		keyed:  true,
+	})
+	if !isUnsupported(err) {
+		return err
+	}
+	return c.emulateTransaction(ctx, tx.ops, opts)
+}
+
+func (c *Client) emulateTransaction(ctx context.Context, ops []txOp, opts []CallOption) error {
+	type created struct {
+		kind Kind
+		id   string
+	}
+	// A caller's WithIdempotencyKey covers the whole transaction; each create
+	// gets its own key derived from it so the server doesn't replay the
+	// first create's response for the others.
+	key := resolveCallOptions(opts).idempotencyKey
+	var done []created
+	for i, op := range ops {
+		opOpts := opts
+		if key != "" {
+			opOpts = append(slices.Clip(opts), WithIdempotencyKey(key+"/"+strconv.Itoa(i)))
+		}
+		id, err := c.createOne(ctx, op, opOpts)
+		if err == nil {
+			done = append(done, created{op.Kind, id})
+			continue
+		}
+
+		err = fmt.Errorf("transaction: operation %d (%s): %w", i, op.Kind, err)
+		// The transaction context may be what failed; roll back regardless.
+		rctx := context.WithoutCancel(ctx)
+		for j := len(done) - 1; j >= 0; j-- {
+			if rerr := c.deleteOne(rctx, done[j].kind, done[j].id); rerr != nil {
+				err = errors.Join(err, fmt.Errorf("rollback %s %s: %w", done[j].kind, done[j].id, rerr))
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+var txPaths = map[Kind]string{
+	KindDecision: "/adr",
+	KindIssue:    "/failure",
+	KindChange:   "/changes",
+}
+
+func (c *Client) createOne(ctx context.Context, op txOp, opts []CallOption) (string, error) {
+	var resp struct {
+		ID string `json:"id"`
+	}
+	err := c.do(ctx, call{
+		op:     "Transaction",
+		method: http.MethodPost,
+		path:   txPaths[op.Kind],
+		in:     op.Data,
+		out:    &resp,
+		opts:   opts,
+		keyed:  true,
+	})
+	if err == nil && resp.ID == "" {
+		err = errors.New("server returned no id")
+	}
+	return resp.ID, err
+}
+
+func (c *Client) deleteOne(ctx context.Context, kind Kind, id string) error {
+	return c.do(ctx, call{
+		op:     "Transaction",
+		method: http.MethodDelete,
+		path:   txPaths[kind] + "/" + url.PathEscape(id),
+	})
+}
+
+// DeleteADR removes decision id. With WithSoftDelete the server keeps a
+// tombstone that RestoreADR can bring back.
+func (c *Client) DeleteADR(ctx context.Context, id string, opts ...CallOption) error {
+	var q url.Values
+	if resolveCallOptions(opts).softDelete {
+		q = url.Values{"soft": {"true"}}
+	}
+	return c.do(ctx, call{
+		op:     "DeleteADR",
+		method: http.MethodDelete,
+		path:   "/adr/" + url.PathEscape(id),
+		query:  q,
+		opts:   opts,
+	})
+}
+
+// WithSoftDelete makes DeleteADR tombstone the decision instead of removing
+// it for good. Soft-deleted decisions are left out of ListADRs unless
+// ADRFilter.IncludeDeleted is set.
+func WithSoftDelete() CallOption {
+	return func(o *callOptions) {
+		o.softDelete = true
+	}
+}
+
+// RestoreADR undoes a soft delete of decision id.
+func (c *Client) RestoreADR(ctx context.Context, id string, opts ...CallOption) error {
+	return c.do(ctx, call{
+		op:     "RestoreADR",
+		method: http.MethodPost,
+		path:   "/adr/" + url.PathEscape(id) + "/restore",
+		opts:   opts,
+		keyed:  true,
+	})
+}
@@ -0,0 +1,66 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTransactionEmulationRollsBack(t *testing.T) {
+	var log []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log = append(log, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/batch/transaction":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && r.URL.Path == "/adr":
+			fmt.Fprint(w, `{"id":"adr-1"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/failure":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL).Transaction(context.Background(), func(tx *Tx) error {
+		if err := tx.CreateADR(ADRRequest{Title: "t", Decision: "d"}); err != nil {
+			return err
+		}
+		return tx.RecordFailure(FailureRequest{Title: "t", RootCause: "rc", Severity: SeverityLow})
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("err = %v", err)
+	}
+	want := "POST /batch/transaction,POST /adr,POST /failure,DELETE /adr/adr-1"
+	if got := strings.Join(log, ","); got != want {
+		t.Errorf("requests = %s", got)
+	}
+}
+
+func TestTransactionEmulationDerivesKeys(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if r.URL.Path == "/batch/transaction" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"id":"%d"}`, len(keys))
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL).Transaction(context.Background(), func(tx *Tx) error {
+		tx.CreateADR(ADRRequest{Title: "t", Decision: "d"})
+		return tx.CreateChange(ChangeRequest{Type: ChangeFeature, Title: "v1"})
+	}, WithIdempotencyKey("k"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(keys, ","); got != "k,k/0,k/1" {
+		t.Errorf("keys = %s", got)
+	}
+}
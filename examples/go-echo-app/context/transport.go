@@ -0,0 +1,84 @@
+package context
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultTimeout bounds each call, retries included, unless WithCallTimeout
+// or an earlier context deadline says otherwise.
+const defaultTimeout = 10 * time.Second
+
+// WithHTTPClient sends requests through hc instead of the client's own
+// http.Client. Transport options such as WithUnixSocket are then ignored:
+// configure them on hc.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.customClient = hc
+	}
+}
+
+// WithUnixSocket dials the Unix domain socket at path for every request,
+// whatever host BaseURL names, e.g.
+//
+//	NewClient("http://context/api", WithUnixSocket("/run/context.sock"))
+//
+// It has no effect together with WithHTTPClient.
+func WithUnixSocket(path string) Option {
+	return func(c *Client) {
+		c.unixSocket = path
+	}
+}
+
+// WithConnPool sizes the connection pool of the client's transport:
+// maxIdle idle connections in total and maxIdlePerHost per host, at most
+// maxConnsPerHost connections per host (0 means no limit), and idle
+// connections closed after idleTimeout. Raise maxIdlePerHost, which
+// net/http defaults to 2, when many calls run concurrently so connections
+// are reused rather than reopened. It has no effect together with
+// WithHTTPClient.
+func WithConnPool(maxIdle, maxIdlePerHost, maxConnsPerHost int, idleTimeout time.Duration) Option {
+	return func(c *Client) {
+		c.pool = &connPool{
+			maxIdle:         maxIdle,
+			maxIdlePerHost:  maxIdlePerHost,
+			maxConnsPerHost: maxConnsPerHost,
+			idleTimeout:     idleTimeout,
+		}
+	}
+}
+
+type connPool struct {
+	maxIdle, maxIdlePerHost, maxConnsPerHost int
+	idleTimeout                              time.Duration
+}
+
+// httpClient builds the http.Client once options are applied.
+func (c *Client) httpClient() *http.Client {
+	if c.customClient != nil {
+		return c.customClient
+	}
+	hc := &http.Client{}
+	if c.unixSocket == "" && c.pool == nil && c.dnsCache == nil {
+		return hc
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if c.unixSocket != "" {
+		var d net.Dialer
+		t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return d.DialContext(ctx, "unix", c.unixSocket)
+		}
+	} else if c.dnsCache != nil {
+		t.DialContext = c.dnsCache.dialContext
+	}
+	if p := c.pool; p != nil {
+		t.MaxIdleConns = p.maxIdle
+		t.MaxIdleConnsPerHost = p.maxIdlePerHost
+		t.MaxConnsPerHost = p.maxConnsPerHost
+		t.IdleConnTimeout = p.idleTimeout
+	}
+	hc.Transport = t
+	return hc
+}
@@ -0,0 +1,48 @@
+package context
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUnixSocket(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "context.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skip("unix sockets unavailable:", err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/health" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+	}))
+	srv.Listener = ln
+	srv.Start()
+	defer srv.Close()
+
+	c := NewClient("http://context/api", WithUnixSocket(sock))
+	if err := c.Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConnPool(t *testing.T) {
+	c := NewClient("http://unused", WithConnPool(50, 10, 20, time.Minute))
+	tr, ok := c.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport = %T", c.client.Transport)
+	}
+	if tr.MaxIdleConns != 50 || tr.MaxIdleConnsPerHost != 10 || tr.MaxConnsPerHost != 20 || tr.IdleConnTimeout != time.Minute {
+		t.Errorf("pool = %d/%d/%d/%v", tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost, tr.IdleConnTimeout)
+	}
+
+	hc := &http.Client{}
+	if c := NewClient("http://unused", WithConnPool(50, 10, 20, time.Minute), WithHTTPClient(hc)); c.client != hc || hc.Transport != nil {
+		t.Error("WithConnPool changed a caller's http.Client")
+	}
+}
@@ -0,0 +1,133 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ADRUpdate is a partial update for an ADR. A nil field is left untouched;
+// a non-nil field replaces the stored value, so a pointer to an empty slice
+// clears it. Every field of ADRRequest is patchable: Title, Decision,
+// Context, OptionsConsidered, Tags, Stakeholders, Owners, Evidence and
+// Visibility.
+//
+//	c.UpdateADR(ctx, id, ADRUpdate{
+//		Decision: String("Use Echo v5"),
+//		Tags:     Strings(), // clear tags
+//	})
+type ADRUpdate struct {
+	Title             *string              `json:"title,omitempty"`
+	Decision          *string              `json:"decision,omitempty"`
+	Context           *string              `json:"context,omitempty"`
+	OptionsConsidered *map[string][]string `json:"options_considered,omitempty"`
+	Tags              *[]string            `json:"tags,omitempty"`
+	Stakeholders      *[]string            `json:"stakeholders,omitempty"`
+	Owners            *[]string            `json:"owners,omitempty"`
+	Evidence          *[]EvidenceRef       `json:"evidence,omitempty"`
+	Visibility        *Visibility          `json:"visibility,omitempty"`
+}
+
+// String returns a pointer to s, for ADRUpdate fields.
+func String(s string) *string { return &s }
+
+// Strings returns a pointer to a slice holding ss, for ADRUpdate fields.
+// With no arguments it points to an empty slice, which clears the field.
+func Strings(ss ...string) *[]string {
+	out := append([]string{}, ss...)
+	return &out
+}
+
+// Validate rejects an update that sets nothing, blanks Title or Decision,
+// or adds evidence without a Ref.
+func (u ADRUpdate) Validate() error {
+	v := newValidator("ADRUpdate")
+	if u == (ADRUpdate{}) {
+		v.add("", "no fields set")
+	}
+	if u.Title != nil {
+		v.require("title", *u.Title)
+	}
+	if u.Decision != nil {
+		v.require("decision", *u.Decision)
+	}
+	if u.Evidence != nil {
+		for i, e := range *u.Evidence {
+			v.require(fmt.Sprintf("evidence[%d].ref", i), e.Ref)
+		}
+	}
+	return v.err()
+}
+
+// WithIfMatch makes an update apply only if the ADR is still at version
+// etag, usually Decision.ETag from GetADR. If someone else changed it first
+// the call fails with an error matching ErrConflict.
+//
+//	adr, _ := c.GetADR(ctx, id)
+//	err := c.UpdateADR(ctx, id, update, WithIfMatch(adr.ETag))
+//	if errors.Is(err, ErrConflict) {
+//		// reload and retry
+//	}
+func WithIfMatch(etag string) CallOption {
+	return func(o *callOptions) {
+		o.ifMatch = etag
+	}
+}
+
+// UpdateADR applies update to ADR id, changing only the fields it sets.
+func (c *Client) UpdateADR(ctx context.Context, id string, update ADRUpdate, opts ...CallOption) error {
+	if err := update.Validate(); err != nil {
+		return err
+	}
+	return c.do(ctx, call{
+		op:     "UpdateADR",
+		method: http.MethodPatch,
+		path:   "/adr/" + url.PathEscape(id),
+		in:     update,
+		opts:   opts,
+	})
+}
+
+// PatchOp is one RFC 6902 JSON Patch operation. Path is a JSON Pointer into
+// the ADR, e.g. "/decision" or "/tags/-" to append a tag.
+type PatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+	From  string `json:"from,omitempty"`
+}
+
+// PatchReplace returns a "replace" op setting path to value.
+func PatchReplace(path string, value any) PatchOp {
+	return PatchOp{Op: "replace", Path: path, Value: value}
+}
+
+// PatchAdd returns an "add" op. For arrays, a path ending in "/-" appends.
+func PatchAdd(path string, value any) PatchOp {
+	return PatchOp{Op: "add", Path: path, Value: value}
+}
+
+// PatchRemove returns a "remove" op.
+func PatchRemove(path string) PatchOp {
+	return PatchOp{Op: "remove", Path: path}
+}
+
+// ReplaceDecision returns the op that sets the decision text.
+func ReplaceDecision(text string) PatchOp { return PatchReplace("/decision", text) }
+
+// AddTag returns the op that appends tag to the ADR's tags.
+func AddTag(tag string) PatchOp { return PatchAdd("/tags/-", tag) }
+
+// PatchADR applies patch to ADR id as an application/json-patch+json
+// document. The server applies all ops or none.
+func (c *Client) PatchADR(ctx context.Context, id string, patch []PatchOp, opts ...CallOption) error {
+	return c.do(ctx, call{
+		op:          "PatchADR",
+		method:      http.MethodPatch,
+		path:        "/adr/" + url.PathEscape(id),
+		in:          patch,
+		opts:        opts,
+		contentType: "application/json-patch+json",
+	})
+}
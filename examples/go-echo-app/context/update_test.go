@@ -0,0 +1,78 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpdateADRSendsOnlySetFields(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/adr/adr-1" {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL).UpdateADR(context.Background(), "adr-1", ADRUpdate{
+		Decision: String("new"),
+		Tags:     Strings(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(body); got != "map[decision:new tags:[]]" {
+		t.Errorf("body = %s", got)
+	}
+}
+
+func TestPatchADRSendsJSONPatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json-patch+json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		b, _ := io.ReadAll(r.Body)
+		want := `[{"op":"replace","path":"/decision","value":"d"},{"op":"add","path":"/tags/-","value":"go"}]`
+		if string(b) != want {
+			t.Errorf("body = %s", b)
+		}
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL).PatchADR(context.Background(), "adr-1", []PatchOp{ReplaceDecision("d"), AddTag("go")})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateADRIfMatchConflict(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("ETag", `"v1"`)
+			fmt.Fprint(w, `{"adr":{"id":"adr-1"}}`)
+			return
+		}
+		if r.Header.Get("If-Match") != `"v1"` {
+			t.Errorf("If-Match = %q", r.Header.Get("If-Match"))
+		}
+		w.WriteHeader(http.StatusPreconditionFailed)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	adr, err := c.GetADR(context.Background(), "adr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.UpdateADR(context.Background(), "adr-1", ADRUpdate{Decision: String("d")}, WithIfMatch(adr.ETag))
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("err = %v, want ErrConflict", err)
+	}
+}
@@ -0,0 +1,159 @@
+package context
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FieldError is one problem with one request field.
+type FieldError struct {
+	Field   string // JSON field name, or "" for the request as a whole
+	Problem string
+}
+
+// ValidationError lists every problem Validate found, so callers can fix
+// them all at once. Nothing is sent to the server when it is returned.
+type ValidationError struct {
+	Request string // e.g. "ADRRequest"
+	Fields  []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Problem
+		if f.Field != "" {
+			parts[i] = f.Field + ": " + f.Problem
+		}
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Request, strings.Join(parts, "; "))
+}
+
+// Missing returns the fields reported as required but empty.
+func (e *ValidationError) Missing() []string {
+	var out []string
+	for _, f := range e.Fields {
+		if f.Problem == problemRequired {
+			out = append(out, f.Field)
+		}
+	}
+	return out
+}
+
+const problemRequired = "required"
+
+type validator struct {
+	ValidationError
+}
+
+func newValidator(request string) *validator {
+	return &validator{ValidationError{Request: request}}
+}
+
+func (v *validator) require(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, problemRequired)
+	}
+}
+
+func (v *validator) add(field, problem string) {
+	v.Fields = append(v.Fields, FieldError{Field: field, Problem: problem})
+}
+
+// timeRange rejects an after/before pair that can match nothing, reporting
+// it against the before field. Either end may be zero for an open range.
+func (v *validator) timeRange(afterField, beforeField string, after, before time.Time) {
+	if !after.IsZero() && !before.IsZero() && !after.Before(before) {
+		v.add(beforeField, "must be after "+afterField)
+	}
+}
+
+// option rejects a considered option with a blank name, reported against
+// nameField, or with nothing listed under it, reported against field.
+func (v *validator) option(nameField, field, name string, pros []string) {
+	if strings.TrimSpace(name) == "" {
+		v.add(nameField, "blank option name")
+		return
+	}
+	if len(pros) == 0 {
+		v.add(field, fmt.Sprintf("option %q lists nothing", name))
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.Fields) == 0 {
+		return nil
+	}
+	return &v.ValidationError
+}
+
+// Validate checks the fields the server requires: Title and Decision, and a
+// Ref on every Evidence entry. Options considered are optional, but each one
+// given needs a name and at least one entry; OptionsConsidered and
+// OptionsConsideredOrdered may not both be set, and ordered options may not
+// repeat a name.
+func (r ADRRequest) Validate() error {
+	v := newValidator("ADRRequest")
+	v.require("title", r.Title)
+	v.require("decision", r.Decision)
+	for i, e := range r.Evidence {
+		v.require(fmt.Sprintf("evidence[%d].ref", i), e.Ref)
+	}
+	if len(r.OptionsConsidered) > 0 && len(r.OptionsConsideredOrdered) > 0 {
+		v.add("options_considered_ordered", "cannot be combined with options_considered")
+	}
+	names := make([]string, 0, len(r.OptionsConsidered))
+	for name := range r.OptionsConsidered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v.option("options_considered", fmt.Sprintf("options_considered[%q]", name), name, r.OptionsConsidered[name])
+	}
+	seen := make(map[string]bool, len(r.OptionsConsideredOrdered))
+	for i, o := range r.OptionsConsideredOrdered {
+		field := fmt.Sprintf("options_considered_ordered[%d]", i)
+		if seen[o.Name] {
+			v.add(field+".name", fmt.Sprintf("duplicate option %q", o.Name))
+		}
+		seen[o.Name] = true
+		v.option(field+".name", field, o.Name, o.Pros)
+	}
+	if r.DedupBy != "" && !r.DedupBy.valid() {
+		v.add("dedup_by", fmt.Sprintf("unknown value %q", r.DedupBy))
+	}
+	return v.err()
+}
+
+// Validate checks the fields the server requires: Title, RootCause and
+// Severity.
+func (r FailureRequest) Validate() error {
+	v := newValidator("FailureRequest")
+	v.require("title", r.Title)
+	v.require("root_cause", r.RootCause)
+	v.require("severity", string(r.Severity))
+	if r.Severity != "" && !r.Severity.Valid() {
+		v.add("severity", fmt.Sprintf("unknown value %q", r.Severity))
+	}
+	return v.err()
+}
+
+// Validate checks the fields the server requires: Type and Title.
+func (r ChangeRequest) Validate() error {
+	v := newValidator("ChangeRequest")
+	v.require("type", string(r.Type))
+	v.require("title", r.Title)
+	return v.err()
+}
+
+// Validate checks the fields the server requires: Title, Approach and
+// Outcome.
+func (r SuccessRequest) Validate() error {
+	v := newValidator("SuccessRequest")
+	v.require("title", r.Title)
+	v.require("approach", r.Approach)
+	v.require("outcome", r.Outcome)
+	return v.err()
+}
@@ -0,0 +1,77 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateListsAllMissingFields(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL).RecordFailure(context.Background(), FailureRequest{Title: "only a title"})
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("err = %v, want *ValidationError", err)
+	}
+	if got := strings.Join(ve.Missing(), ","); got != "root_cause,severity" {
+		t.Errorf("Missing() = %q", got)
+	}
+	if calls != 0 {
+		t.Errorf("server called %d times, want 0", calls)
+	}
+}
+
+func TestValidateOptionsConsidered(t *testing.T) {
+	base := ADRRequest{Title: "t", Decision: "d"}
+	if err := base.Validate(); err != nil {
+		t.Fatalf("no options: %v", err)
+	}
+
+	half := base
+	half.OptionsConsidered = map[string][]string{"echo": {"fast"}, "gin": {}, " ": {"x"}}
+	var ve *ValidationError
+	if !errors.As(half.Validate(), &ve) {
+		t.Fatal("half-filled options accepted")
+	}
+	var fields []string
+	for _, f := range ve.Fields {
+		fields = append(fields, f.Field)
+	}
+	if got := strings.Join(fields, ","); got != `options_considered,options_considered["gin"]` {
+		t.Errorf("fields = %s", got)
+	}
+
+	ordered := base
+	ordered.OptionsConsideredOrdered = []ConsideredOption{{Name: "echo", Pros: []string{"fast"}}, {Name: "gin"}}
+	if !errors.As(ordered.Validate(), &ve) || len(ve.Fields) != 1 || ve.Fields[0].Field != "options_considered_ordered[1]" {
+		t.Errorf("ordered: %v", ordered.Validate())
+	}
+}
+
+func TestValidateEvidence(t *testing.T) {
+	req := ADRRequest{Title: "t", Decision: "d", Evidence: []EvidenceRef{
+		{Type: "benchmark", Ref: "https://bench/1"},
+		{Type: "doc"},
+		{Type: "benchmark", Ref: "  ", Description: "blank ref"},
+	}}
+	var ve *ValidationError
+	if err := req.Validate(); !errors.As(err, &ve) {
+		t.Fatalf("err = %v, want *ValidationError", err)
+	}
+	if got := strings.Join(ve.Missing(), ","); got != "evidence[1].ref,evidence[2].ref" {
+		t.Errorf("Missing() = %q", got)
+	}
+
+	req.Evidence = req.Evidence[:1]
+	if err := req.Validate(); err != nil {
+		t.Errorf("valid evidence: %v", err)
+	}
+}
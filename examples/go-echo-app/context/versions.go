@@ -0,0 +1,52 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ADRVersion is one entry in an ADR's edit history.
+type ADRVersion struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// Author is who made the change, as the server identifies them.
+	Author string `json:"author,omitempty"`
+}
+
+// ListADRVersions returns the versions of ADR id, as the server orders them.
+func (c *Client) ListADRVersions(ctx context.Context, id string, opts ...CallOption) ([]ADRVersion, error) {
+	var versions []ADRVersion
+	err := c.do(ctx, call{
+		op:     "ListADRVersions",
+		method: http.MethodGet,
+		path:   "/adr/" + url.PathEscape(id) + "/versions",
+		out:    &versions,
+		opts:   opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetADRVersion returns ADR id as it was at version, a number from
+// ListADRVersions.
+func (c *Client) GetADRVersion(ctx context.Context, id string, version int, opts ...CallOption) (*Decision, error) {
+	var resp struct {
+		ADR Decision `json:"adr"`
+	}
+	err := c.do(ctx, call{
+		op:     "GetADRVersion",
+		method: http.MethodGet,
+		path:   "/adr/" + url.PathEscape(id) + "/versions/" + strconv.Itoa(version),
+		out:    &resp,
+		opts:   opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.ADR, nil
+}
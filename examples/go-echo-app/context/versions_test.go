@@ -0,0 +1,42 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestADRVersions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/adr/a%2Fb/versions":
+			w.Write([]byte(`[{"version":1,"created_at":"2026-01-01T00:00:00Z","author":"ana"},
+				{"version":2,"created_at":"2026-02-01T00:00:00Z","author":"bo"}]`))
+		case "/adr/a%2Fb/versions/1":
+			w.Write([]byte(`{"adr":{"id":"a/b","title":"Use gin","decision":"gin","tags":[]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	versions, err := c.ListADRVersions(context.Background(), "a/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ADRVersion{
+		{Version: 1, CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Author: "ana"},
+		{Version: 2, CreatedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), Author: "bo"},
+	}
+	if !slices.Equal(versions, want) {
+		t.Errorf("versions = %+v", versions)
+	}
+	d, err := c.GetADRVersion(context.Background(), "a/b", 1)
+	if err != nil || d.Title != "Use gin" {
+		t.Errorf("GetADRVersion = %+v, %v", d, err)
+	}
+}
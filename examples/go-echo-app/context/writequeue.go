@@ -0,0 +1,232 @@
+package context
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQueued is wrapped by the error CreateADR, RecordFailure and
+// RecordSuccess return when the write could not be delivered and was saved
+// to the write queue instead. It will be replayed with the same
+// Idempotency-Key, so callers may usually treat it as success.
+var ErrQueued = errors.New("write queued for replay")
+
+const queueReplayInterval = 30 * time.Second
+
+// WithWriteQueue persists writes that fail with a transport error, 429 or
+// 5xx under dir and replays them in the background once Ping succeeds.
+// Writes rejected with other 4xx statuses, or whose context is canceled,
+// are never queued. Close stops the replayer and makes a last attempt to
+// drain the queue.
+func WithWriteQueue(dir string) Option {
+	return func(c *Client) {
+		c.queue = &writeQueue{dir: dir}
+	}
+}
+
+type writeQueue struct {
+	dir string
+	mu  sync.Mutex // serializes Flush
+}
+
+type queuedWrite struct {
+	Op       string          `json:"op"`
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	Key      string          `json:"idempotency_key"`
+	Body     json.RawMessage `json:"body"`
+	QueuedAt time.Time       `json:"queued_at"`
+}
+
+const pendingExt, rejectedExt = ".json", ".rejected"
+
+// write sends a durable write, falling back to the queue when configured.
+func (c *Client) write(ctx context.Context, cl call) error {
+	o := resolveCallOptions(cl.opts)
+	if c.queue == nil || o.dryRun {
+		return c.do(ctx, cl)
+	}
+	if c.isClosed() {
+		// The replayer has stopped, so a queued write would sit on disk.
+		return ErrClientClosed
+	}
+
+	// Fix the key now so the replay reuses it.
+	key := o.idempotencyKey
+	if key == "" {
+		key = c.idempotencyKey()
+		cl.opts = append(cl.opts[:len(cl.opts):len(cl.opts)], WithIdempotencyKey(key))
+	}
+
+	err := c.do(ctx, cl)
+	if err == nil || !queueable(err) {
+		return err
+	}
+	if qerr := c.queue.save(cl, key); qerr != nil {
+		return errors.Join(err, qerr)
+	}
+	return fmt.Errorf("%w: %w", ErrQueued, err)
+}
+
+// queueable reports whether err means the write may succeed later. A call
+// the caller canceled was given up on, so it is not.
+func queueable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	var me *MarshalError
+	return !errors.As(err, &me)
+}
+
+func (q *writeQueue) save(cl call, key string) error {
+	body, err := json.Marshal(cl.in)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(queuedWrite{
+		Op:       cl.op,
+		Method:   cl.method,
+		Path:     cl.path,
+		Key:      key,
+		Body:     body,
+		QueuedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(q.dir, 0o700); err != nil {
+		return fmt.Errorf("write queue: %w", err)
+	}
+
+	// Name by time so replays keep the original order; write then rename
+	// so a crash never leaves a partial entry. The key may come from the
+	// caller, so only its hash goes in the name; the key itself is in the
+	// entry.
+	sum := sha256.Sum256([]byte(key))
+	name := strconv.FormatInt(time.Now().UnixNano(), 10) + "-" + hex.EncodeToString(sum[:8])
+	tmp := filepath.Join(q.dir, name+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write queue: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(q.dir, name+pendingExt)); err != nil {
+		return fmt.Errorf("write queue: %w", err)
+	}
+	return nil
+}
+
+func (q *writeQueue) pending() []string {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), pendingExt) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PendingCount returns the number of queued writes. It is 0 without
+// WithWriteQueue.
+func (c *Client) PendingCount() int {
+	if c.queue == nil {
+		return 0
+	}
+	return len(c.queue.pending())
+}
+
+// Flush replays queued writes in order. It stops at the first write that
+// fails in a way that would be queued again and returns that error. Writes
+// the server rejects outright are renamed with a .rejected extension, kept
+// for inspection, and reported in the returned error.
+func (c *Client) Flush(ctx context.Context) error {
+	q := c.queue
+	if q == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var rejected []error
+	for _, name := range q.pending() {
+		path := filepath.Join(q.dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("write queue: %w", err)
+		}
+		var w queuedWrite
+		if err := json.Unmarshal(data, &w); err != nil {
+			rejected = append(rejected, fmt.Errorf("%s: %w", name, err))
+			_ = os.Rename(path, strings.TrimSuffix(path, pendingExt)+rejectedExt)
+			continue
+		}
+
+		err = c.do(ctx, call{
+			op:     w.Op,
+			method: w.Method,
+			path:   w.Path,
+			in:     c.replayBody(w.Body),
+			opts:   []CallOption{WithIdempotencyKey(w.Key)},
+		})
+		switch {
+		case err == nil:
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("write queue: %w", err)
+			}
+		case queueable(err) || errors.Is(err, context.Canceled):
+			// Keep the entry for the next Flush.
+			return errors.Join(append(rejected, err)...)
+		default:
+			rejected = append(rejected, fmt.Errorf("%s %s: %w", w.Op, w.Key, err))
+			_ = os.Rename(path, strings.TrimSuffix(path, pendingExt)+rejectedExt)
+		}
+	}
+	return errors.Join(rejected...)
+}
+
+func (c *Client) replayLoop() {
+	defer c.wg.Done()
+	t := time.NewTicker(queueReplayInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-t.C:
+		}
+		if c.PendingCount() == 0 {
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-c.done:
+			case <-ctx.Done():
+			}
+			cancel()
+		}()
+		if c.Ping(ctx) == nil {
+			_ = c.Flush(ctx)
+		}
+		cancel()
+	}
+}
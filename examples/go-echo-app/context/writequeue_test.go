@@ -0,0 +1,176 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWriteQueueReplaysWithSameKey(t *testing.T) {
+	var keys []string
+	down := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithWriteQueue(t.TempDir()))
+	defer c.Close(context.Background())
+
+	err := c.RecordFailure(context.Background(), FailureRequest{Title: "t", RootCause: "rc", Severity: SeverityHigh})
+	if !errors.Is(err, ErrQueued) {
+		t.Fatalf("err = %v, want ErrQueued", err)
+	}
+	if n := c.PendingCount(); n != 1 {
+		t.Fatalf("PendingCount = %d, want 1", n)
+	}
+
+	down = false
+	if err := c.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := c.PendingCount(); n != 0 {
+		t.Errorf("PendingCount after Flush = %d", n)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("keys = %q, want the same key twice", keys)
+	}
+}
+
+func TestCloseDrainsWriteQueue(t *testing.T) {
+	var down, stall atomic.Bool
+	down.Store(true)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if stall.Load() {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	dir := t.TempDir()
+	fail := FailureRequest{Title: "t", RootCause: "rc", Severity: SeverityHigh}
+
+	// A server that never answers leaves the write queued for next time.
+	c := NewClient(srv.URL, WithWriteQueue(dir))
+	if err := c.RecordFailure(context.Background(), fail); !errors.Is(err, ErrQueued) {
+		t.Fatalf("err = %v, want ErrQueued", err)
+	}
+	stall.Store(true)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("stalled Close err = %v, want DeadlineExceeded", err)
+	}
+	if n := c.PendingCount(); n != 1 {
+		t.Fatalf("PendingCount after stalled Close = %d, want 1", n)
+	}
+
+	stall.Store(false)
+	down.Store(false)
+	c = NewClient(srv.URL, WithWriteQueue(dir))
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := c.PendingCount(); n != 0 {
+		t.Errorf("PendingCount after Close = %d, want 0", n)
+	}
+	if err := c.Close(context.Background()); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestWriteQueueCallerKeyStaysInDir(t *testing.T) {
+	var keys []string
+	down := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "queue")
+	c := NewClient(srv.URL, WithWriteQueue(dir))
+	defer c.Close(context.Background())
+
+	key := "evt/../../escaped"
+	err := c.RecordFailure(context.Background(), FailureRequest{Title: "t", RootCause: "rc", Severity: SeverityHigh}, WithIdempotencyKey(key))
+	if !errors.Is(err, ErrQueued) {
+		t.Fatalf("err = %v, want ErrQueued", err)
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 1 {
+		t.Errorf("files outside the queue dir: %v", entries)
+	}
+
+	down = false
+	if err := c.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[1] != key {
+		t.Errorf("keys = %q", keys)
+	}
+}
+
+func TestWriteQueueAfterClose(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, WithWriteQueue(t.TempDir()))
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	err := c.CreateADR(context.Background(), ADRRequest{Title: "t", Decision: "d"})
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("err = %v", err)
+	}
+	if n := c.PendingCount(); n != 0 || hits.Load() != 0 {
+		t.Errorf("PendingCount = %d, requests = %d", n, hits.Load())
+	}
+}
+
+func TestWriteQueueSkipsCancelledWrites(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := NewClient(srv.URL, WithWriteQueue(t.TempDir()))
+	defer c.Close(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	err := c.CreateADR(ctx, ADRRequest{Title: "t", Decision: "d"})
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrQueued) {
+		t.Errorf("err = %v", err)
+	}
+	if n := c.PendingCount(); n != 0 {
+		t.Errorf("PendingCount = %d", n)
+	}
+}
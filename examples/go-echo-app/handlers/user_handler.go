@@ -3,9 +3,11 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"net/mail"
 	"strconv"
 
 	"github.com/example/go-echo-app/context"
+	"github.com/example/go-echo-app/context/contextecho"
 	"github.com/example/go-echo-app/models"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -56,6 +58,15 @@ func (h *UserHandler) GetUser(c echo.Context) error {
 	return c.JSON(http.StatusOK, user)
 }
 
+// CreateUserQuery is the context query for CreateUser, for use with
+// contextecho.Inject.
+func CreateUserQuery(echo.Context) context.QueryRequest {
+	return context.NewQuery("user management validation email").
+		WithDomains(context.DomainValidation, context.DomainUsers).
+		WithMinScore(0.5).
+		Build()
+}
+
 func (h *UserHandler) CreateUser(c echo.Context) error {
 	user := new(models.User)
 	if err := c.Bind(user); err != nil {
@@ -64,32 +75,28 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 		})
 	}
 
-	ctx, err := h.context.Query(context.QueryRequest{
-		Query:   "user management validation email",
-		Domains: []string{"validation", "users"},
-	})
-	if err == nil && len(ctx.KeyDecisions) > 0 {
-		fmt.Printf("📚 Context check: Found %d relevant decisions\n", len(ctx.KeyDecisions))
-		for _, dec := range ctx.KeyDecisions {
+	if result, ok := contextecho.FromContext(c); ok && len(result.KeyDecisions) > 0 {
+		fmt.Printf("📚 Context check: Found %d relevant decisions\n", len(result.KeyDecisions))
+		for _, dec := range result.KeyDecisions {
 			fmt.Printf("  - %s: %s\n", dec.ID, dec.Title)
 		}
+
+		// Only act on context we're confident about; weaker matches are
+		// logged above but don't change behavior.
+		if confident := result.DecisionsAbove(h.context.ConfidenceThreshold()); len(confident) > 0 {
+			if _, err := mail.ParseAddress(user.Email); err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error":    "Invalid email address",
+					"decision": confident[0].ID,
+				})
+			}
+		}
 	}
 
+	// Returned rather than written so contextecho.RecordFailures records
+	// the database error as the root cause.
 	if err := h.db.Create(user).Error; err != nil {
-		_ = h.context.RecordFailure(context.FailureRequest{
-			Title:      "User Creation Failed",
-			RootCause:  fmt.Sprintf("Database error: %v", err),
-			Symptoms:   "POST /users returned 500",
-			Impact:     "User registration blocked",
-			Resolution: "Investigating...",
-			Severity:   "medium",
-			Pattern:    "database_error",
-			Tags:       []string{"users", "database"},
-		})
-
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to create user",
-		})
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create user").SetInternal(err)
 	}
 
 	return c.JSON(http.StatusCreated, user)
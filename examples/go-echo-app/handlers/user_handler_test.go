@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/example/go-echo-app/context"
+	"github.com/example/go-echo-app/context/contextecho"
+	"github.com/labstack/echo/v4"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCreateUserRecordsRootCause(t *testing.T) {
+	failures := make(chan context.FailureRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req context.FailureRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		failures <- req
+	}))
+	defer srv.Close()
+
+	// No migration, so the insert fails.
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := context.NewClient(srv.URL)
+	e := echo.New()
+	e.Use(contextecho.RecordFailures(client))
+	e.POST("/users", NewUserHandler(db, client).CreateUser)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Ann","email":"ann@example.com"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d", rec.Code)
+	}
+
+	select {
+	case f := <-failures:
+		if !strings.Contains(f.RootCause, "no such table: users") {
+			t.Errorf("root cause = %q", f.RootCause)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no failure recorded")
+	}
+}
@@ -1,10 +1,18 @@
 package main
 
 import (
+	stdcontext "context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/example/go-echo-app/context"
+	"github.com/example/go-echo-app/context/contextecho"
+	"github.com/example/go-echo-app/context/contextgorm"
 	"github.com/example/go-echo-app/handlers"
 	"github.com/example/go-echo-app/models"
 	"github.com/labstack/echo/v4"
@@ -27,9 +35,21 @@ func main() {
 	if contextURL == "" {
 		contextURL = "http://localhost:4000/api"
 	}
-	contextClient := context.NewClient(contextURL)
+	contextClient := context.NewClient(contextURL,
+		context.WithDomainValidation(),
+		context.WithConfidenceThreshold(0.8),
+	)
+	if err := contextgorm.Register(db, contextClient, contextgorm.WithTags("users-api")); err != nil {
+		log.Fatal("Failed to register context hooks:", err)
+	}
+	if domains, err := contextClient.LoadDomains(stdcontext.Background()); err != nil {
+		log.Printf("⚠️  Could not load context domains, skipping validation: %v", err)
+	} else {
+		log.Printf("📚 Loaded %d context domains", len(domains))
+	}
 
-	_ = contextClient.CreateADR(context.ADRRequest{
+	// GetOrCreateADR so restarts don't record the decision again.
+	if _, _, err := contextClient.GetOrCreateADR(stdcontext.Background(), context.ADRRequest{
 		Title:    "Use Echo Framework for Go REST API",
 		Decision: "Selected Echo as the web framework for its simplicity and performance",
 		Context:  "Need lightweight HTTP router with middleware support for REST API",
@@ -46,23 +66,44 @@ func main() {
 		},
 		Tags:         []string{"golang", "web-framework", "rest-api"},
 		Stakeholders: []string{"backend-team"},
-	})
+		Evidence: []context.EvidenceRef{
+			{
+				Type:        "benchmark",
+				Ref:         "https://github.com/labstack/echo#benchmarks",
+				Description: "Echo routing benchmarks against other Go routers",
+			},
+			{
+				Type:        "benchmark",
+				Ref:         "https://github.com/gin-gonic/gin/blob/master/BENCHMARKS.md",
+				Description: "Gin's published router benchmarks for comparison",
+			},
+		},
+	}); err != nil {
+		log.Printf("⚠️  Could not record framework ADR: %v", err)
+	}
 
 	e := echo.New()
 
 	e.Use(middleware.Logger())
+	// Outside Recover so recovered panics are recorded too.
+	e.Use(contextecho.RecordFailures(contextClient, contextecho.WithTags("users-api")))
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
 
 	userHandler := handlers.NewUserHandler(db, contextClient)
 
 	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(200, map[string]string{"status": "ok"})
+		health := map[string]string{"status": "ok", "context_engine": "ok"}
+		if err := contextClient.Ping(c.Request().Context()); err != nil {
+			health["context_engine"] = "unreachable"
+		}
+		return c.JSON(200, health)
 	})
 
 	e.GET("/users", userHandler.GetUsers)
 	e.GET("/users/:id", userHandler.GetUser)
-	e.POST("/users", userHandler.CreateUser)
+	e.POST("/users", userHandler.CreateUser,
+		contextecho.Inject(contextClient, handlers.CreateUserQuery, contextecho.WithTimeout(2*time.Second)))
 	e.PUT("/users/:id", userHandler.UpdateUser)
 	e.DELETE("/users/:id", userHandler.DeleteUser)
 
@@ -72,7 +113,7 @@ func main() {
 			return c.JSON(400, map[string]string{"error": "Invalid request"})
 		}
 
-		result, err := contextClient.Query(req)
+		result, err := contextClient.Query(c.Request().Context(), req)
 		if err != nil {
 			return c.JSON(500, map[string]string{"error": err.Error()})
 		}
@@ -87,5 +128,23 @@ func main() {
 
 	log.Printf("🚀 Server starting on :%s", port)
 	log.Printf("📚 Context Engineering at: %s", contextURL)
-	log.Fatal(e.Start(":" + port))
+	go func() {
+		if err := e.Start(":" + port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	stop, cancel := signal.NotifyContext(stdcontext.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	<-stop.Done()
+
+	ctx, cancelShutdown := stdcontext.WithTimeout(stdcontext.Background(), 10*time.Second)
+	defer cancelShutdown()
+	if err := e.Shutdown(ctx); err != nil {
+		log.Printf("Server shutdown: %v", err)
+	}
+	// Sends buffered failures and drains the write queue.
+	if err := contextClient.Close(ctx); err != nil {
+		log.Printf("⚠️  Context client shutdown: %v", err)
+	}
 }